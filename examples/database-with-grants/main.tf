@@ -0,0 +1,12 @@
+# Example: Snowflake Database and Schema Grants
+#
+# This example demonstrates how to use the database-schema module
+# to grant privileges on a database and its schemas to account roles.
+# Every grants list supported by the module is configured, so the
+# example can be used to verify each privilege end to end.
+
+module "database" {
+  source = "../.."
+
+  database_configs = var.database_configs
+}
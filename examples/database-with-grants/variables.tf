@@ -0,0 +1,161 @@
+variable "database_configs" {
+  description = "Map of configuration objects for Snowflake databases and their schemas"
+  type = map(object({
+    name                        = string
+    comment                     = optional(string, null)
+    data_retention_time_in_days = optional(number, 1)
+    is_transient                = optional(bool, false)
+    owner_role                  = optional(string, null)
+    owner_outbound_privileges   = optional(string, "COPY")
+    grants = optional(object({
+      usage_roles                = optional(list(string), [])
+      monitor_roles              = optional(list(string), [])
+      create_schema_roles        = optional(list(string), [])
+      modify_roles               = optional(list(string), [])
+      create_database_role_roles = optional(list(string), [])
+      privileges                 = optional(map(list(string)), {})
+      }), {
+      usage_roles                = []
+      monitor_roles              = []
+      create_schema_roles        = []
+      modify_roles               = []
+      create_database_role_roles = []
+      privileges                 = {}
+    })
+    database_roles = optional(map(object({
+      comment                  = optional(string, null)
+      granted_to_account_roles = optional(list(string), [])
+      database_privileges      = optional(list(string), [])
+      schema_privileges        = optional(map(list(string)), {})
+    })), {})
+    schemas = optional(list(object({
+      name                        = string
+      comment                     = optional(string, null)
+      is_transient                = optional(bool, false)
+      is_managed                  = optional(bool, false)
+      data_retention_time_in_days = optional(number, null)
+      owner_role                  = optional(string, null)
+      owner_outbound_privileges   = optional(string, "COPY")
+      grants = optional(object({
+        usage_roles                    = optional(list(string), [])
+        create_file_format_roles       = optional(list(string), [])
+        create_stage_roles             = optional(list(string), [])
+        create_table_roles             = optional(list(string), [])
+        create_pipe_roles              = optional(list(string), [])
+        create_view_roles              = optional(list(string), [])
+        create_materialized_view_roles = optional(list(string), [])
+        create_sequence_roles          = optional(list(string), [])
+        create_function_roles          = optional(list(string), [])
+        create_procedure_roles         = optional(list(string), [])
+        create_stream_roles            = optional(list(string), [])
+        create_task_roles              = optional(list(string), [])
+        create_dynamic_table_roles     = optional(list(string), [])
+        monitor_roles                  = optional(list(string), [])
+        privileges                     = optional(map(list(string)), {})
+        }), {
+        usage_roles                    = []
+        create_file_format_roles       = []
+        create_stage_roles             = []
+        create_table_roles             = []
+        create_pipe_roles              = []
+        create_view_roles              = []
+        create_materialized_view_roles = []
+        create_sequence_roles          = []
+        create_function_roles          = []
+        create_procedure_roles         = []
+        create_stream_roles            = []
+        create_task_roles              = []
+        create_dynamic_table_roles     = []
+        monitor_roles                  = []
+        privileges                     = {}
+      })
+      future_grants      = optional(map(map(list(string))), {})
+      all_objects_grants = optional(map(map(list(string))), {})
+      access_roles = optional(object({
+        enabled                = optional(bool, true)
+        ro_granted_to_roles    = optional(list(string), [])
+        rw_granted_to_roles    = optional(list(string), [])
+        owner_granted_to_roles = optional(list(string), [])
+      }), {})
+    })), [])
+  }))
+  default = {
+    app = {
+      name    = "APPLICATION_DB"
+      comment = "Application database with grants"
+      grants = {
+        usage_roles         = ["DATA_READER_ROLE", "DATA_WRITER_ROLE", "ETL_ROLE"]
+        monitor_roles       = ["DATA_READER_ROLE"]
+        create_schema_roles = ["ETL_ROLE"]
+      }
+      database_roles = {
+        RAW_READ = {
+          comment                  = "Read access to the RAW schema"
+          granted_to_account_roles = ["DATA_READER_ROLE"]
+          database_privileges      = ["USAGE"]
+          schema_privileges = {
+            RAW = ["USAGE"]
+          }
+        }
+      }
+      schemas = [
+        {
+          name    = "RAW"
+          comment = "Raw ingested data"
+          grants = {
+            usage_roles              = ["DATA_READER_ROLE", "DATA_WRITER_ROLE", "ETL_ROLE"]
+            create_file_format_roles = ["ETL_ROLE"]
+            create_stage_roles       = ["ETL_ROLE"]
+            create_table_roles       = ["DATA_WRITER_ROLE", "ETL_ROLE"]
+            create_pipe_roles        = ["ETL_ROLE"]
+            create_view_roles        = ["DATA_WRITER_ROLE"]
+            create_task_roles        = ["ETL_ROLE"]
+            monitor_roles            = ["DATA_READER_ROLE"]
+            privileges = {
+              "CREATE TAG" = ["DATA_WRITER_ROLE"]
+            }
+          }
+          future_grants = {
+            TABLES = { SELECT = ["DATA_READER_ROLE"] }
+            VIEWS  = { SELECT = ["DATA_READER_ROLE"] }
+          }
+          all_objects_grants = {
+            TABLES = { SELECT = ["DATA_READER_ROLE"] }
+          }
+        }
+      ]
+    }
+  }
+}
+
+# Snowflake authentication variables
+variable "snowflake_organization_name" {
+  description = "Snowflake organization name"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_account_name" {
+  description = "Snowflake account name"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_user" {
+  description = "Snowflake username"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_role" {
+  description = "Snowflake role"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_private_key" {
+  description = "Snowflake private key for key-pair authentication"
+  type        = string
+  sensitive   = true
+  default     = null
+}
@@ -0,0 +1,11 @@
+# Example: Snowflake Database with Parameters
+#
+# This example demonstrates how to use the database-schema module
+# to set Snowflake parameters on a database and override some of them
+# on individual schemas. Parameters left null are inherited.
+
+module "database" {
+  source = "../.."
+
+  database_configs = var.database_configs
+}
@@ -0,0 +1,92 @@
+variable "database_configs" {
+  description = "Map of configuration objects for Snowflake databases and their schemas"
+  type = map(object({
+    name                        = string
+    comment                     = optional(string, null)
+    data_retention_time_in_days = optional(number, 1)
+    is_transient                = optional(bool, false)
+    # Parameters left null inherit the account value
+    max_data_extension_time_in_days = optional(number, null)
+    default_ddl_collation           = optional(string, null)
+    external_volume                 = optional(string, null)
+    catalog                         = optional(string, null)
+    replace_invalid_characters      = optional(bool, null)
+    storage_serialization_policy    = optional(string, null)
+    log_level                       = optional(string, null)
+    trace_level                     = optional(string, null)
+    event_table                     = optional(string, null)
+    schemas = optional(list(object({
+      name                        = string
+      comment                     = optional(string, null)
+      is_transient                = optional(bool, false)
+      is_managed                  = optional(bool, false)
+      data_retention_time_in_days = optional(number, null)
+      # Parameters left null inherit the database value
+      max_data_extension_time_in_days = optional(number, null)
+      default_ddl_collation           = optional(string, null)
+      external_volume                 = optional(string, null)
+      catalog                         = optional(string, null)
+      replace_invalid_characters      = optional(bool, null)
+      storage_serialization_policy    = optional(string, null)
+      log_level                       = optional(string, null)
+      trace_level                     = optional(string, null)
+    })), [])
+  }))
+  default = {
+    lake = {
+      name                            = "LAKE_DB"
+      comment                         = "Database with parameters set for its schemas to inherit"
+      data_retention_time_in_days     = 7
+      max_data_extension_time_in_days = 30
+      default_ddl_collation           = "en-ci"
+      storage_serialization_policy    = "OPTIMIZED"
+      log_level                       = "WARN"
+      trace_level                     = "ON_EVENT"
+      schemas = [
+        {
+          name = "RAW"
+        },
+        {
+          name                            = "ICEBERG"
+          comment                         = "Schema overriding the database parameters"
+          max_data_extension_time_in_days = 14
+          replace_invalid_characters      = true
+          storage_serialization_policy    = "COMPATIBLE"
+          log_level                       = "DEBUG"
+        }
+      ]
+    }
+  }
+}
+
+# Snowflake authentication variables
+variable "snowflake_organization_name" {
+  description = "Snowflake organization name"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_account_name" {
+  description = "Snowflake account name"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_user" {
+  description = "Snowflake username"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_role" {
+  description = "Snowflake role"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_private_key" {
+  description = "Snowflake private key for key-pair authentication"
+  type        = string
+  sensitive   = true
+  default     = null
+}
@@ -0,0 +1,12 @@
+# Example: Snowflake Database with Schemas Keyed by a Stable Identifier
+#
+# This example demonstrates how to use the database-schema module
+# to declare schemas in the schemas_by_key map. Each schema is keyed by an
+# identifier that does not change, so editing its name renames the schema
+# in place instead of replacing it.
+
+module "database" {
+  source = "../.."
+
+  database_configs = var.database_configs
+}
@@ -0,0 +1,65 @@
+variable "database_configs" {
+  description = "Map of configuration objects for Snowflake databases and their schemas"
+  type = map(object({
+    name                        = string
+    comment                     = optional(string, null)
+    data_retention_time_in_days = optional(number, 1)
+    is_transient                = optional(bool, false)
+    schemas_by_key = optional(map(object({
+      name                        = string
+      comment                     = optional(string, null)
+      is_transient                = optional(bool, false)
+      is_managed                  = optional(bool, false)
+      data_retention_time_in_days = optional(number, null)
+    })), {})
+  }))
+  default = {
+    analytics = {
+      name    = "ANALYTICS_DB"
+      comment = "Analytics database"
+      schemas_by_key = {
+        staging = {
+          name    = "STAGING"
+          comment = "Data landed from source systems"
+        }
+        marts = {
+          name       = "MARTS"
+          comment    = "Reporting data marts"
+          is_managed = true
+        }
+      }
+    }
+  }
+}
+
+# Snowflake authentication variables
+variable "snowflake_organization_name" {
+  description = "Snowflake organization name"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_account_name" {
+  description = "Snowflake account name"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_user" {
+  description = "Snowflake username"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_role" {
+  description = "Snowflake role"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_private_key" {
+  description = "Snowflake private key for key-pair authentication"
+  type        = string
+  sensitive   = true
+  default     = null
+}
@@ -0,0 +1,11 @@
+# Example: Snowflake Database with Tags
+#
+# This example demonstrates how to use the database-schema module
+# to tag a database and its schemas. The tags themselves must already
+# exist; the module only creates the tag associations.
+
+module "database" {
+  source = "../.."
+
+  database_configs = var.database_configs
+}
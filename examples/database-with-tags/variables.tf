@@ -0,0 +1,72 @@
+variable "database_configs" {
+  description = "Map of configuration objects for Snowflake databases and their schemas"
+  type = map(object({
+    name                        = string
+    comment                     = optional(string, null)
+    data_retention_time_in_days = optional(number, 1)
+    is_transient                = optional(bool, false)
+    tags                        = optional(map(string), {})
+    schemas = optional(list(object({
+      name                        = string
+      comment                     = optional(string, null)
+      is_transient                = optional(bool, false)
+      is_managed                  = optional(bool, false)
+      data_retention_time_in_days = optional(number, null)
+      tags                        = optional(map(string), {})
+    })), [])
+  }))
+  default = {
+    finance = {
+      name    = "FINANCE_DB"
+      comment = "Finance database classified with governance tags"
+      tags = {
+        "GOVERNANCE_DB.TAGS.COST_CENTER" = "FIN-001"
+        "GOVERNANCE_DB.TAGS.SENSITIVITY" = "internal"
+      }
+      schemas = [
+        {
+          name = "REPORTS"
+        },
+        {
+          name    = "LEDGER"
+          comment = "Schema with a stricter sensitivity than its database"
+          tags = {
+            "GOVERNANCE_DB.TAGS.SENSITIVITY" = "confidential"
+          }
+        }
+      ]
+    }
+  }
+}
+
+# Snowflake authentication variables
+variable "snowflake_organization_name" {
+  description = "Snowflake organization name"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_account_name" {
+  description = "Snowflake account name"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_user" {
+  description = "Snowflake username"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_role" {
+  description = "Snowflake role"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_private_key" {
+  description = "Snowflake private key for key-pair authentication"
+  type        = string
+  sensitive   = true
+  default     = null
+}
@@ -4,7 +4,7 @@ terraform {
   required_providers {
     snowflake = {
       source  = "snowflakedb/snowflake"
-      version = ">= 0.87.0"
+      version = ">= 1.0.0"
     }
   }
 }
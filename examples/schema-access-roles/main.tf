@@ -0,0 +1,13 @@
+# Example: Generated Schema Access Roles
+#
+# This example demonstrates how to use the database-schema module
+# to generate RO, RW and OWNER access roles for every schema.
+# RO is granted to RW, RW to OWNER and OWNER to the parent role,
+# and each level is handed to the functional roles listed per schema.
+
+module "database" {
+  source = "../.."
+
+  database_configs = var.database_configs
+  access_roles     = var.access_roles
+}
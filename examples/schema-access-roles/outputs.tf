@@ -0,0 +1,14 @@
+output "database_names" {
+  description = "Map of database config keys to database names"
+  value       = module.database.database_names
+}
+
+output "schema_fully_qualified_names" {
+  description = "Nested map of database keys to schema fully qualified names"
+  value       = module.database.schema_fully_qualified_names
+}
+
+output "access_role_names" {
+  description = "Nested map of database keys to schema names to generated access role names by level"
+  value       = module.database.access_role_names
+}
@@ -0,0 +1,89 @@
+variable "database_configs" {
+  description = "Map of configuration objects for Snowflake databases and their schemas"
+  type = map(object({
+    name                        = string
+    comment                     = optional(string, null)
+    data_retention_time_in_days = optional(number, 1)
+    is_transient                = optional(bool, false)
+    schemas = optional(list(object({
+      name                        = string
+      comment                     = optional(string, null)
+      is_transient                = optional(bool, false)
+      is_managed                  = optional(bool, false)
+      data_retention_time_in_days = optional(number, null)
+      access_roles = optional(object({
+        enabled                = optional(bool, true)
+        ro_granted_to_roles    = optional(list(string), [])
+        rw_granted_to_roles    = optional(list(string), [])
+        owner_granted_to_roles = optional(list(string), [])
+      }), {})
+    })), [])
+  }))
+  default = {
+    sales = {
+      name    = "SALES_DB"
+      comment = "Sales database with generated access roles"
+      schemas = [
+        {
+          name    = "CURATED"
+          comment = "Curated sales data"
+          access_roles = {
+            ro_granted_to_roles    = ["ANALYST_ROLE"]
+            rw_granted_to_roles    = ["ETL_ROLE"]
+            owner_granted_to_roles = ["SALES_ENGINEER_ROLE"]
+          }
+        },
+        {
+          name         = "SCRATCH"
+          comment      = "Scratch space without generated roles"
+          access_roles = { enabled = false }
+        }
+      ]
+    }
+  }
+}
+
+variable "access_roles" {
+  description = "Generation settings for the schema access roles"
+  type = object({
+    enabled       = optional(bool, false)
+    name_template = optional(string, "{database}_{schema}_{level}")
+    parent_role   = optional(string, null)
+  })
+  default = {
+    enabled     = true
+    parent_role = "SYSADMIN"
+  }
+}
+
+# Snowflake authentication variables
+variable "snowflake_organization_name" {
+  description = "Snowflake organization name"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_account_name" {
+  description = "Snowflake account name"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_user" {
+  description = "Snowflake username"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_role" {
+  description = "Snowflake role"
+  type        = string
+  default     = null
+}
+
+variable "snowflake_private_key" {
+  description = "Snowflake private key for key-pair authentication"
+  type        = string
+  sensitive   = true
+  default     = null
+}
@@ -7,33 +7,195 @@
 # -----------------------------------------------------------------------------
 
 locals {
-  schemas = merge([
+  # Schemas keyed "<db_key>.<name>" for the schemas list and
+  # "<db_key>.<key>" for schemas_by_key, where renaming keeps the key.
+  # Database keys cannot contain ".", so two databases never share a key.
+  schemas = merge(flatten([
+    for db_key, db in var.database_configs : [
+      {
+        for schema in db.schemas :
+        "${db_key}.${schema.name}" => {
+          db_key        = db_key
+          database_name = db.name
+          schema        = schema
+        }
+      },
+      {
+        for key, schema in db.schemas_by_key :
+        "${db_key}.${key}" => {
+          db_key        = db_key
+          database_name = db.name
+          schema        = schema
+        }
+      },
+    ]
+  ])...)
+
+  # Schema keys by "<db_key>.<schema name>", for settings that name a schema
+  schema_keys_by_name = {
+    for schema_key, schema_data in local.schemas : "${schema_data.db_key}.${schema_data.schema.name}" => schema_key
+  }
+
+  # Databases that send logs and traces to an event table
+  database_event_tables = {
+    for db_key, db in var.database_configs : db_key => db.event_table
+    if db.event_table != null
+  }
+
+  # Tag associations keyed "<db_key>/<tag>" and "<schema_key>/<tag>"
+  database_tags = merge([
     for db_key, db in var.database_configs : {
-      for schema in db.schemas :
-      "${db_key}.${schema.name}" => {
-        db_key        = db_key
-        database_name = db.name
-        schema        = schema
+      for tag, value in db.tags :
+      "${db_key}/${tag}" => {
+        db_key = db_key
+        tag    = tag
+        value  = value
       }
     }
   ]...)
 
-  # Flatten database grants for iteration
+  schema_tags = merge([
+    for schema_key, schema_data in local.schemas : {
+      for tag, value in schema_data.schema.tags :
+      "${schema_key}/${tag}" => {
+        schema_key = schema_key
+        tag        = tag
+        value      = value
+      }
+    }
+  ]...)
+
+  # Databases and schemas whose ownership moves to owner_role
+  database_ownership = {
+    for db_key, db in var.database_configs : db_key => db
+    if db.owner_role != null
+  }
+
+  schema_ownership = {
+    for schema_key, schema_data in local.schemas : schema_key => schema_data
+    if schema_data.schema.owner_role != null
+  }
+
+  # Flatten database grants for iteration. Key parts are joined with "/",
+  # which database keys, schema names and database role names cannot contain.
+  # Database keys cannot contain "." either, so schema keys and database role
+  # keys ("<db_key>.<name>") split at their first "." and every key splits
+  # back into the same parts.
   database_usage_grants = merge([
     for db_key, db in var.database_configs : {
       for role in db.grants.usage_roles :
-      "${db_key}_${role}" => {
+      "${db_key}/${role}" => {
+        db_key = db_key
+        role   = role
+      }
+    }
+  ]...)
+
+  database_monitor_grants = merge([
+    for db_key, db in var.database_configs : {
+      for role in db.grants.monitor_roles :
+      "${db_key}/${role}" => {
         db_key = db_key
         role   = role
       }
     }
   ]...)
 
+  database_create_schema_grants = merge([
+    for db_key, db in var.database_configs : {
+      for role in db.grants.create_schema_roles :
+      "${db_key}/${role}" => {
+        db_key = db_key
+        role   = role
+      }
+    }
+  ]...)
+
+  database_modify_grants = merge([
+    for db_key, db in var.database_configs : {
+      for role in db.grants.modify_roles :
+      "${db_key}/${role}" => {
+        db_key = db_key
+        role   = role
+      }
+    }
+  ]...)
+
+  database_create_database_role_grants = merge([
+    for db_key, db in var.database_configs : {
+      for role in db.grants.create_database_role_roles :
+      "${db_key}/${role}" => {
+        db_key = db_key
+        role   = role
+      }
+    }
+  ]...)
+
+  # Flatten the generic database privileges map (privilege => roles)
+  database_privilege_grants = merge(flatten([
+    for db_key, db in var.database_configs : [
+      for privilege, roles in db.grants.privileges : {
+        for role in roles :
+        "${db_key}/${upper(privilege)}/${role}" => {
+          db_key    = db_key
+          privilege = upper(privilege)
+          role      = role
+        }
+      }
+    ]
+  ])...)
+
+  # Database roles, keyed "<db_key>.<role_name>"
+  database_roles = merge([
+    for db_key, db in var.database_configs : {
+      for role_name, role in db.database_roles :
+      "${db_key}.${role_name}" => {
+        db_key    = db_key
+        role_name = role_name
+        role      = role
+      }
+    }
+  ]...)
+
+  database_role_database_grants = merge([
+    for role_key, role_data in local.database_roles : {
+      for privilege in role_data.role.database_privileges :
+      "${role_key}/${upper(privilege)}" => {
+        role_key  = role_key
+        db_key    = role_data.db_key
+        privilege = upper(privilege)
+      }
+    }
+  ]...)
+
+  database_role_schema_grants = merge(flatten([
+    for role_key, role_data in local.database_roles : [
+      for schema_name, privileges in role_data.role.schema_privileges : {
+        for privilege in privileges :
+        "${role_key}/${schema_name}/${upper(privilege)}" => {
+          role_key   = role_key
+          schema_key = local.schema_keys_by_name["${role_data.db_key}.${schema_name}"]
+          privilege  = upper(privilege)
+        }
+      }
+    ]
+  ])...)
+
+  database_role_account_role_grants = merge([
+    for role_key, role_data in local.database_roles : {
+      for account_role in role_data.role.granted_to_account_roles :
+      "${role_key}/${account_role}" => {
+        role_key     = role_key
+        account_role = account_role
+      }
+    }
+  ]...)
+
   # Flatten schema grants for iteration
   schema_usage_grants = merge([
     for schema_key, schema_data in local.schemas : {
       for role in schema_data.schema.grants.usage_roles :
-      "${schema_key}_${role}" => {
+      "${schema_key}/${role}" => {
         schema_key = schema_key
         role       = role
       }
@@ -43,7 +205,7 @@ locals {
   schema_create_file_format_grants = merge([
     for schema_key, schema_data in local.schemas : {
       for role in schema_data.schema.grants.create_file_format_roles :
-      "${schema_key}_${role}" => {
+      "${schema_key}/${role}" => {
         schema_key = schema_key
         role       = role
       }
@@ -53,7 +215,7 @@ locals {
   schema_create_stage_grants = merge([
     for schema_key, schema_data in local.schemas : {
       for role in schema_data.schema.grants.create_stage_roles :
-      "${schema_key}_${role}" => {
+      "${schema_key}/${role}" => {
         schema_key = schema_key
         role       = role
       }
@@ -63,7 +225,7 @@ locals {
   schema_create_table_grants = merge([
     for schema_key, schema_data in local.schemas : {
       for role in schema_data.schema.grants.create_table_roles :
-      "${schema_key}_${role}" => {
+      "${schema_key}/${role}" => {
         schema_key = schema_key
         role       = role
       }
@@ -73,14 +235,229 @@ locals {
   schema_create_pipe_grants = merge([
     for schema_key, schema_data in local.schemas : {
       for role in schema_data.schema.grants.create_pipe_roles :
-      "${schema_key}_${role}" => {
+      "${schema_key}/${role}" => {
+        schema_key = schema_key
+        role       = role
+      }
+    }
+  ]...)
+
+  # Flatten the generic schema privileges map (privilege => roles)
+  schema_privilege_grants = merge(flatten([
+    for schema_key, schema_data in local.schemas : [
+      for privilege, roles in schema_data.schema.grants.privileges : {
+        for role in roles :
+        "${schema_key}/${upper(privilege)}/${role}" => {
+          schema_key = schema_key
+          privilege  = upper(privilege)
+          role       = role
+        }
+      }
+    ]
+  ])...)
+
+  schema_create_view_grants = merge([
+    for schema_key, schema_data in local.schemas : {
+      for role in schema_data.schema.grants.create_view_roles :
+      "${schema_key}/${role}" => {
+        schema_key = schema_key
+        role       = role
+      }
+    }
+  ]...)
+
+  schema_create_materialized_view_grants = merge([
+    for schema_key, schema_data in local.schemas : {
+      for role in schema_data.schema.grants.create_materialized_view_roles :
+      "${schema_key}/${role}" => {
+        schema_key = schema_key
+        role       = role
+      }
+    }
+  ]...)
+
+  schema_create_sequence_grants = merge([
+    for schema_key, schema_data in local.schemas : {
+      for role in schema_data.schema.grants.create_sequence_roles :
+      "${schema_key}/${role}" => {
+        schema_key = schema_key
+        role       = role
+      }
+    }
+  ]...)
+
+  schema_create_function_grants = merge([
+    for schema_key, schema_data in local.schemas : {
+      for role in schema_data.schema.grants.create_function_roles :
+      "${schema_key}/${role}" => {
+        schema_key = schema_key
+        role       = role
+      }
+    }
+  ]...)
+
+  schema_create_procedure_grants = merge([
+    for schema_key, schema_data in local.schemas : {
+      for role in schema_data.schema.grants.create_procedure_roles :
+      "${schema_key}/${role}" => {
         schema_key = schema_key
         role       = role
       }
     }
   ]...)
+
+  schema_create_stream_grants = merge([
+    for schema_key, schema_data in local.schemas : {
+      for role in schema_data.schema.grants.create_stream_roles :
+      "${schema_key}/${role}" => {
+        schema_key = schema_key
+        role       = role
+      }
+    }
+  ]...)
+
+  schema_create_task_grants = merge([
+    for schema_key, schema_data in local.schemas : {
+      for role in schema_data.schema.grants.create_task_roles :
+      "${schema_key}/${role}" => {
+        schema_key = schema_key
+        role       = role
+      }
+    }
+  ]...)
+
+  schema_create_dynamic_table_grants = merge([
+    for schema_key, schema_data in local.schemas : {
+      for role in schema_data.schema.grants.create_dynamic_table_roles :
+      "${schema_key}/${role}" => {
+        schema_key = schema_key
+        role       = role
+      }
+    }
+  ]...)
+
+  schema_monitor_grants = merge([
+    for schema_key, schema_data in local.schemas : {
+      for role in schema_data.schema.grants.monitor_roles :
+      "${schema_key}/${role}" => {
+        schema_key = schema_key
+        role       = role
+      }
+    }
+  ]...)
+
+  # Flatten schema future grants (object type => privilege => roles)
+  schema_future_grants = merge(flatten([
+    for schema_key, schema_data in local.schemas : [
+      for object_type, privileges in schema_data.schema.future_grants : [
+        for privilege, roles in privileges : {
+          for role in roles :
+          "${schema_key}/${upper(object_type)}/${upper(privilege)}/${role}" => {
+            schema_key  = schema_key
+            object_type = upper(object_type)
+            privilege   = upper(privilege)
+            role        = role
+          }
+        }
+      ]
+    ]
+  ])...)
+
+  # Flatten schema all-objects grants (object type => privilege => roles)
+  schema_all_objects_grants = merge(flatten([
+    for schema_key, schema_data in local.schemas : [
+      for object_type, privileges in schema_data.schema.all_objects_grants : [
+        for privilege, roles in privileges : {
+          for role in roles :
+          "${schema_key}/${upper(object_type)}/${upper(privilege)}/${role}" => {
+            schema_key  = schema_key
+            object_type = upper(object_type)
+            privilege   = upper(privilege)
+            role        = role
+          }
+        }
+      ]
+    ]
+  ])...)
+
+  # Privilege bundles of the generated access roles. Each level is granted to
+  # the next one, so RW inherits RO and OWNER inherits RW.
+  access_role_bundles = {
+    RO = {
+      parent            = "RW"
+      schema_privileges = ["USAGE"]
+      object_privileges = { TABLES = ["SELECT"], VIEWS = ["SELECT"], "MATERIALIZED VIEWS" = ["SELECT"] }
+    }
+    RW = {
+      parent            = "OWNER"
+      schema_privileges = []
+      object_privileges = { TABLES = ["INSERT", "UPDATE", "DELETE", "TRUNCATE"] }
+    }
+    OWNER = {
+      parent = null
+      schema_privileges = [
+        "CREATE TABLE", "CREATE VIEW", "CREATE MATERIALIZED VIEW", "CREATE STAGE", "CREATE FILE FORMAT",
+        "CREATE SEQUENCE", "CREATE FUNCTION", "CREATE PROCEDURE", "CREATE STREAM", "CREATE TASK",
+        "CREATE PIPE", "CREATE DYNAMIC TABLE", "MODIFY", "MONITOR",
+      ]
+      object_privileges = {}
+    }
+  }
+
+  # Generated access roles, keyed "<schema_key>_<level>"
+  access_roles = merge([
+    for schema_key, schema_data in local.schemas : {
+      for level, bundle in local.access_role_bundles :
+      "${schema_key}_${level}" => {
+        schema_key = schema_key
+        db_key     = schema_data.db_key
+        level      = level
+        bundle     = bundle
+        members    = schema_data.schema.access_roles["${lower(level)}_granted_to_roles"]
+        name       = replace(replace(replace(var.access_roles.name_template, "{database}", schema_data.database_name), "{schema}", schema_data.schema.name), "{level}", level)
+      }
+    } if var.access_roles.enabled && schema_data.schema.access_roles.enabled
+  ]...)
+
+  access_role_schema_grants = {
+    for role_key, role in local.access_roles : role_key => role
+    if length(role.bundle.schema_privileges) > 0
+  }
+
+  access_role_object_grants = merge([
+    for role_key, role in local.access_roles : {
+      for object_type, privileges in role.bundle.object_privileges :
+      "${role_key}_${object_type}" => {
+        role_key    = role_key
+        schema_key  = role.schema_key
+        object_type = object_type
+        privileges  = privileges
+      }
+    }
+  ]...)
+
+  # Generated parents are referenced by key; the top level goes to parent_role
+  access_role_hierarchy = {
+    for role_key, role in local.access_roles : role_key => {
+      role_key    = role_key
+      parent_key  = role.bundle.parent != null ? "${role.schema_key}_${role.bundle.parent}" : null
+      parent_role = var.access_roles.parent_role
+    } if role.bundle.parent != null || var.access_roles.parent_role != null
+  }
+
+  access_role_members = merge([
+    for role_key, role in local.access_roles : {
+      for member in role.members :
+      "${role_key}/${member}" => {
+        role_key = role_key
+        member   = member
+      }
+    }
+  ]...)
 }
 
+# Keyed by the database_configs key, so a new name renames the database in
+# place instead of replacing it
 resource "snowflake_database" "this" {
   for_each = var.database_configs
 
@@ -88,8 +465,19 @@ resource "snowflake_database" "this" {
   comment                     = each.value.comment
   data_retention_time_in_days = each.value.data_retention_time_in_days
   is_transient                = each.value.is_transient
+
+  max_data_extension_time_in_days = each.value.max_data_extension_time_in_days
+  default_ddl_collation           = each.value.default_ddl_collation
+  external_volume                 = each.value.external_volume
+  catalog                         = each.value.catalog
+  replace_invalid_characters      = each.value.replace_invalid_characters
+  storage_serialization_policy    = each.value.storage_serialization_policy == null ? null : upper(each.value.storage_serialization_policy)
+  log_level                       = each.value.log_level == null ? null : upper(each.value.log_level)
+  trace_level                     = each.value.trace_level == null ? null : upper(each.value.trace_level)
 }
 
+# The provider replaces a schema whose database argument changes, so renaming
+# a database that holds schemas needs the state steps in README, Renaming.
 resource "snowflake_schema" "this" {
   for_each = local.schemas
 
@@ -99,6 +487,149 @@ resource "snowflake_schema" "this" {
   is_transient                = each.value.schema.is_transient
   with_managed_access         = each.value.schema.is_managed
   data_retention_time_in_days = each.value.schema.data_retention_time_in_days
+
+  max_data_extension_time_in_days = each.value.schema.max_data_extension_time_in_days
+  default_ddl_collation           = each.value.schema.default_ddl_collation
+  external_volume                 = each.value.schema.external_volume
+  catalog                         = each.value.schema.catalog
+  replace_invalid_characters      = each.value.schema.replace_invalid_characters
+  storage_serialization_policy    = each.value.schema.storage_serialization_policy == null ? null : upper(each.value.schema.storage_serialization_policy)
+  log_level                       = each.value.schema.log_level == null ? null : upper(each.value.schema.log_level)
+  trace_level                     = each.value.schema.trace_level == null ? null : upper(each.value.schema.trace_level)
+}
+
+# The database resource has no event_table argument, so the parameter is set
+# on its own. snowflake_object_parameter is a preview resource: the calling
+# configuration's provider block must list snowflake_object_parameter_resource
+# in preview_features_enabled.
+resource "snowflake_object_parameter" "database_event_table" {
+  for_each = local.database_event_tables
+
+  key         = "EVENT_TABLE"
+  value       = each.value
+  object_type = "DATABASE"
+
+  object_identifier {
+    name = snowflake_database.this[each.key].name
+  }
+}
+
+# -----------------------------------------------------------------------------
+# Tags
+# -----------------------------------------------------------------------------
+
+resource "snowflake_tag_association" "database" {
+  for_each = local.database_tags
+
+  object_identifiers = [snowflake_database.this[each.value.db_key].fully_qualified_name]
+  object_type        = "DATABASE"
+  tag_id             = each.value.tag
+  tag_value          = each.value.value
+}
+
+resource "snowflake_tag_association" "schema" {
+  for_each = local.schema_tags
+
+  object_identifiers = [snowflake_schema.this[each.value.schema_key].fully_qualified_name]
+  object_type        = "SCHEMA"
+  tag_id             = each.value.tag
+  tag_value          = each.value.value
+}
+
+# -----------------------------------------------------------------------------
+# Ownership
+# -----------------------------------------------------------------------------
+
+# Ownership moves last: everything the module creates on or in an object is
+# in place while the role running Terraform still owns it. Later changes to
+# a transferred object need that role to inherit owner_role or hold MANAGE
+# GRANTS. See README, Ownership Transfer.
+resource "snowflake_grant_ownership" "database" {
+  for_each = local.database_ownership
+
+  account_role_name   = each.value.owner_role
+  outbound_privileges = upper(each.value.owner_outbound_privileges)
+
+  on {
+    object_type = "DATABASE"
+    object_name = snowflake_database.this[each.key].fully_qualified_name
+  }
+
+  depends_on = [
+    snowflake_schema.this,
+    snowflake_grant_ownership.schema,
+    snowflake_object_parameter.database_event_table,
+    snowflake_tag_association.database,
+    snowflake_tag_association.schema,
+    snowflake_grant_privileges_to_account_role.database_usage,
+    snowflake_grant_privileges_to_account_role.database_monitor,
+    snowflake_grant_privileges_to_account_role.database_create_schema,
+    snowflake_grant_privileges_to_account_role.database_modify,
+    snowflake_grant_privileges_to_account_role.database_create_database_role,
+    snowflake_grant_privileges_to_account_role.database_privileges,
+    snowflake_grant_privileges_to_account_role.schema_usage,
+    snowflake_grant_privileges_to_account_role.schema_create_file_format,
+    snowflake_grant_privileges_to_account_role.schema_create_stage,
+    snowflake_grant_privileges_to_account_role.schema_create_table,
+    snowflake_grant_privileges_to_account_role.schema_create_pipe,
+    snowflake_grant_privileges_to_account_role.schema_create_view,
+    snowflake_grant_privileges_to_account_role.schema_create_materialized_view,
+    snowflake_grant_privileges_to_account_role.schema_create_sequence,
+    snowflake_grant_privileges_to_account_role.schema_create_function,
+    snowflake_grant_privileges_to_account_role.schema_create_procedure,
+    snowflake_grant_privileges_to_account_role.schema_create_stream,
+    snowflake_grant_privileges_to_account_role.schema_create_task,
+    snowflake_grant_privileges_to_account_role.schema_create_dynamic_table,
+    snowflake_grant_privileges_to_account_role.schema_monitor,
+    snowflake_grant_privileges_to_account_role.schema_privileges,
+    snowflake_grant_privileges_to_account_role.schema_future,
+    snowflake_grant_privileges_to_account_role.schema_all_objects,
+    snowflake_database_role.this,
+    snowflake_grant_privileges_to_database_role.database,
+    snowflake_grant_privileges_to_database_role.schema,
+    snowflake_grant_database_role.to_account_role,
+    snowflake_grant_privileges_to_account_role.access_database_usage,
+    snowflake_grant_privileges_to_account_role.access_schema,
+    snowflake_grant_privileges_to_account_role.access_future,
+    snowflake_grant_privileges_to_account_role.access_all,
+  ]
+}
+
+resource "snowflake_grant_ownership" "schema" {
+  for_each = local.schema_ownership
+
+  account_role_name   = each.value.schema.owner_role
+  outbound_privileges = upper(each.value.schema.owner_outbound_privileges)
+
+  on {
+    object_type = "SCHEMA"
+    object_name = snowflake_schema.this[each.key].fully_qualified_name
+  }
+
+  depends_on = [
+    snowflake_tag_association.schema,
+    snowflake_grant_privileges_to_account_role.schema_usage,
+    snowflake_grant_privileges_to_account_role.schema_create_file_format,
+    snowflake_grant_privileges_to_account_role.schema_create_stage,
+    snowflake_grant_privileges_to_account_role.schema_create_table,
+    snowflake_grant_privileges_to_account_role.schema_create_pipe,
+    snowflake_grant_privileges_to_account_role.schema_create_view,
+    snowflake_grant_privileges_to_account_role.schema_create_materialized_view,
+    snowflake_grant_privileges_to_account_role.schema_create_sequence,
+    snowflake_grant_privileges_to_account_role.schema_create_function,
+    snowflake_grant_privileges_to_account_role.schema_create_procedure,
+    snowflake_grant_privileges_to_account_role.schema_create_stream,
+    snowflake_grant_privileges_to_account_role.schema_create_task,
+    snowflake_grant_privileges_to_account_role.schema_create_dynamic_table,
+    snowflake_grant_privileges_to_account_role.schema_monitor,
+    snowflake_grant_privileges_to_account_role.schema_privileges,
+    snowflake_grant_privileges_to_account_role.schema_future,
+    snowflake_grant_privileges_to_account_role.schema_all_objects,
+    snowflake_grant_privileges_to_database_role.schema,
+    snowflake_grant_privileges_to_account_role.access_schema,
+    snowflake_grant_privileges_to_account_role.access_future,
+    snowflake_grant_privileges_to_account_role.access_all,
+  ]
 }
 
 # -----------------------------------------------------------------------------
@@ -118,6 +649,71 @@ resource "snowflake_grant_privileges_to_account_role" "database_usage" {
   }
 }
 
+# Database MONITOR grants
+resource "snowflake_grant_privileges_to_account_role" "database_monitor" {
+  for_each = local.database_monitor_grants
+
+  privileges        = ["MONITOR"]
+  account_role_name = each.value.role
+
+  on_account_object {
+    object_type = "DATABASE"
+    object_name = snowflake_database.this[each.value.db_key].fully_qualified_name
+  }
+}
+
+# Database CREATE SCHEMA grants
+resource "snowflake_grant_privileges_to_account_role" "database_create_schema" {
+  for_each = local.database_create_schema_grants
+
+  privileges        = ["CREATE SCHEMA"]
+  account_role_name = each.value.role
+
+  on_account_object {
+    object_type = "DATABASE"
+    object_name = snowflake_database.this[each.value.db_key].fully_qualified_name
+  }
+}
+
+# Database MODIFY grants
+resource "snowflake_grant_privileges_to_account_role" "database_modify" {
+  for_each = local.database_modify_grants
+
+  privileges        = ["MODIFY"]
+  account_role_name = each.value.role
+
+  on_account_object {
+    object_type = "DATABASE"
+    object_name = snowflake_database.this[each.value.db_key].fully_qualified_name
+  }
+}
+
+# Database CREATE DATABASE ROLE grants
+resource "snowflake_grant_privileges_to_account_role" "database_create_database_role" {
+  for_each = local.database_create_database_role_grants
+
+  privileges        = ["CREATE DATABASE ROLE"]
+  account_role_name = each.value.role
+
+  on_account_object {
+    object_type = "DATABASE"
+    object_name = snowflake_database.this[each.value.db_key].fully_qualified_name
+  }
+}
+
+# Database grants from the generic privileges map
+resource "snowflake_grant_privileges_to_account_role" "database_privileges" {
+  for_each = local.database_privilege_grants
+
+  privileges        = [each.value.privilege]
+  account_role_name = each.value.role
+
+  on_account_object {
+    object_type = "DATABASE"
+    object_name = snowflake_database.this[each.value.db_key].fully_qualified_name
+  }
+}
+
 # -----------------------------------------------------------------------------
 # Schema Grants
 # -----------------------------------------------------------------------------
@@ -180,4 +776,292 @@ resource "snowflake_grant_privileges_to_account_role" "schema_create_pipe" {
   on_schema {
     schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
   }
-}
\ No newline at end of file
+}
+
+# Schema CREATE VIEW grants
+resource "snowflake_grant_privileges_to_account_role" "schema_create_view" {
+  for_each = local.schema_create_view_grants
+
+  privileges        = ["CREATE VIEW"]
+  account_role_name = each.value.role
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+# Schema CREATE MATERIALIZED VIEW grants
+resource "snowflake_grant_privileges_to_account_role" "schema_create_materialized_view" {
+  for_each = local.schema_create_materialized_view_grants
+
+  privileges        = ["CREATE MATERIALIZED VIEW"]
+  account_role_name = each.value.role
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+# Schema CREATE SEQUENCE grants
+resource "snowflake_grant_privileges_to_account_role" "schema_create_sequence" {
+  for_each = local.schema_create_sequence_grants
+
+  privileges        = ["CREATE SEQUENCE"]
+  account_role_name = each.value.role
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+# Schema CREATE FUNCTION grants
+resource "snowflake_grant_privileges_to_account_role" "schema_create_function" {
+  for_each = local.schema_create_function_grants
+
+  privileges        = ["CREATE FUNCTION"]
+  account_role_name = each.value.role
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+# Schema CREATE PROCEDURE grants
+resource "snowflake_grant_privileges_to_account_role" "schema_create_procedure" {
+  for_each = local.schema_create_procedure_grants
+
+  privileges        = ["CREATE PROCEDURE"]
+  account_role_name = each.value.role
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+# Schema CREATE STREAM grants
+resource "snowflake_grant_privileges_to_account_role" "schema_create_stream" {
+  for_each = local.schema_create_stream_grants
+
+  privileges        = ["CREATE STREAM"]
+  account_role_name = each.value.role
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+# Schema CREATE TASK grants
+resource "snowflake_grant_privileges_to_account_role" "schema_create_task" {
+  for_each = local.schema_create_task_grants
+
+  privileges        = ["CREATE TASK"]
+  account_role_name = each.value.role
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+# Schema CREATE DYNAMIC TABLE grants
+resource "snowflake_grant_privileges_to_account_role" "schema_create_dynamic_table" {
+  for_each = local.schema_create_dynamic_table_grants
+
+  privileges        = ["CREATE DYNAMIC TABLE"]
+  account_role_name = each.value.role
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+# Schema MONITOR grants
+resource "snowflake_grant_privileges_to_account_role" "schema_monitor" {
+  for_each = local.schema_monitor_grants
+
+  privileges        = ["MONITOR"]
+  account_role_name = each.value.role
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+# Schema grants from the generic privileges map
+resource "snowflake_grant_privileges_to_account_role" "schema_privileges" {
+  for_each = local.schema_privilege_grants
+
+  privileges        = [each.value.privilege]
+  account_role_name = each.value.role
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+# -----------------------------------------------------------------------------
+# Schema Future Grants
+# -----------------------------------------------------------------------------
+
+# Privileges on objects created in the schema after the grant
+resource "snowflake_grant_privileges_to_account_role" "schema_future" {
+  for_each = local.schema_future_grants
+
+  privileges        = [each.value.privilege]
+  account_role_name = each.value.role
+
+  on_schema_object {
+    future {
+      object_type_plural = each.value.object_type
+      in_schema          = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+    }
+  }
+}
+
+# -----------------------------------------------------------------------------
+# Schema All-Objects Grants
+# -----------------------------------------------------------------------------
+
+# Privileges on every object of a type that exists in the schema at apply time
+resource "snowflake_grant_privileges_to_account_role" "schema_all_objects" {
+  for_each = local.schema_all_objects_grants
+
+  privileges        = [each.value.privilege]
+  account_role_name = each.value.role
+
+  on_schema_object {
+    all {
+      object_type_plural = each.value.object_type
+      in_schema          = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+    }
+  }
+}
+
+# -----------------------------------------------------------------------------
+# Database Roles
+# -----------------------------------------------------------------------------
+
+resource "snowflake_database_role" "this" {
+  for_each = local.database_roles
+
+  name     = each.value.role_name
+  database = snowflake_database.this[each.value.db_key].name
+  comment  = each.value.role.comment
+}
+
+# Database privileges granted to database roles
+resource "snowflake_grant_privileges_to_database_role" "database" {
+  for_each = local.database_role_database_grants
+
+  privileges         = [each.value.privilege]
+  database_role_name = snowflake_database_role.this[each.value.role_key].fully_qualified_name
+  on_database        = snowflake_database.this[each.value.db_key].name
+}
+
+# Schema privileges granted to database roles
+resource "snowflake_grant_privileges_to_database_role" "schema" {
+  for_each = local.database_role_schema_grants
+
+  privileges         = [each.value.privilege]
+  database_role_name = snowflake_database_role.this[each.value.role_key].fully_qualified_name
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+# Database roles granted to account roles
+resource "snowflake_grant_database_role" "to_account_role" {
+  for_each = local.database_role_account_role_grants
+
+  database_role_name = snowflake_database_role.this[each.value.role_key].fully_qualified_name
+  parent_role_name   = each.value.account_role
+}
+
+# -----------------------------------------------------------------------------
+# Generated Access Roles
+# -----------------------------------------------------------------------------
+
+resource "snowflake_account_role" "access" {
+  for_each = local.access_roles
+
+  name    = each.value.name
+  comment = "${each.value.level} access to ${snowflake_schema.this[each.value.schema_key].fully_qualified_name}"
+
+  # The template joins names without escaping, so SALES_EU.RAW and SALES.EU_RAW
+  # both render SALES_EU_RAW_RO with the default. Account role names are
+  # case-insensitive, so they are compared in upper case.
+  lifecycle {
+    precondition {
+      condition     = length([for role in local.access_roles : role if upper(role.name) == upper(each.value.name)]) == 1
+      error_message = "Two schemas render the same access role name from access_roles.name_template. Rename a database or schema, or add separators to the template that the names cannot contain."
+    }
+  }
+}
+
+# Every access role can see the database; RO is enough as RW and OWNER inherit it
+resource "snowflake_grant_privileges_to_account_role" "access_database_usage" {
+  for_each = { for k, v in local.access_roles : k => v if v.level == "RO" }
+
+  privileges        = ["USAGE"]
+  account_role_name = snowflake_account_role.access[each.key].name
+
+  on_account_object {
+    object_type = "DATABASE"
+    object_name = snowflake_database.this[each.value.db_key].fully_qualified_name
+  }
+}
+
+resource "snowflake_grant_privileges_to_account_role" "access_schema" {
+  for_each = local.access_role_schema_grants
+
+  privileges        = each.value.bundle.schema_privileges
+  account_role_name = snowflake_account_role.access[each.key].name
+
+  on_schema {
+    schema_name = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+  }
+}
+
+resource "snowflake_grant_privileges_to_account_role" "access_future" {
+  for_each = local.access_role_object_grants
+
+  privileges        = each.value.privileges
+  account_role_name = snowflake_account_role.access[each.value.role_key].name
+
+  on_schema_object {
+    future {
+      object_type_plural = each.value.object_type
+      in_schema          = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+    }
+  }
+}
+
+resource "snowflake_grant_privileges_to_account_role" "access_all" {
+  for_each = local.access_role_object_grants
+
+  privileges        = each.value.privileges
+  account_role_name = snowflake_account_role.access[each.value.role_key].name
+
+  on_schema_object {
+    all {
+      object_type_plural = each.value.object_type
+      in_schema          = snowflake_schema.this[each.value.schema_key].fully_qualified_name
+    }
+  }
+}
+
+# RO is granted to RW, RW to OWNER and OWNER to access_roles.parent_role
+resource "snowflake_grant_account_role" "access_hierarchy" {
+  for_each = local.access_role_hierarchy
+
+  role_name        = snowflake_account_role.access[each.value.role_key].name
+  parent_role_name = each.value.parent_key != null ? snowflake_account_role.access[each.value.parent_key].name : each.value.parent_role
+}
+
+# Generated access roles granted to the roles listed per schema
+resource "snowflake_grant_account_role" "access_members" {
+  for_each = local.access_role_members
+
+  role_name        = snowflake_account_role.access[each.value.role_key].name
+  parent_role_name = each.value.member
+}
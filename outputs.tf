@@ -43,3 +43,21 @@ output "schemas" {
   description = "All schema resource objects."
   value       = snowflake_schema.this
 }
+
+output "database_role_fully_qualified_names" {
+  description = "Map of <database key>.<database role name> to database role fully qualified names."
+  value       = { for k, v in snowflake_database_role.this : k => v.fully_qualified_name }
+}
+
+output "access_role_names" {
+  description = "Nested map of database keys to schema names to generated access role names by level (RO, RW, OWNER)."
+  value = {
+    for db_key in distinct([for k, v in local.access_roles : v.db_key]) : db_key => {
+      for schema_key in distinct([for k, v in local.access_roles : v.schema_key if v.db_key == db_key]) :
+      local.schemas[schema_key].schema.name => {
+        for k, v in local.access_roles : v.level => snowflake_account_role.access[k].name
+        if v.schema_key == schema_key
+      }
+    }
+  }
+}
@@ -0,0 +1,33 @@
+// File: test/config_helpers_test.go
+package test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+)
+
+// ptr returns a pointer to v, for the nullable config fields
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// exampleVars builds the Vars map shared by every example: the database
+// configs plus the Snowflake connection variables.
+func exampleVars(t *testing.T, databaseConfigs dbconfig.DatabaseConfigs) map[string]interface{} {
+	t.Helper()
+
+	value, err := databaseConfigs.TerraformValue()
+	require.NoError(t, err)
+
+	return map[string]interface{}{
+		"database_configs":            value,
+		"snowflake_organization_name": os.Getenv("SNOWFLAKE_ORGANIZATION_NAME"),
+		"snowflake_account_name":      os.Getenv("SNOWFLAKE_ACCOUNT_NAME"),
+		"snowflake_user":              os.Getenv("SNOWFLAKE_USER"),
+		"snowflake_role":              os.Getenv("SNOWFLAKE_ROLE"),
+		"snowflake_private_key":       os.Getenv("SNOWFLAKE_PRIVATE_KEY"),
+	}
+}
@@ -0,0 +1,106 @@
+// File: test/config_test.go
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+)
+
+// TestDatabaseConfigsTerraformValue verifies the typed configs marshal to the
+// attribute names in variables.tf and leave unset optional attributes out
+func TestDatabaseConfigsTerraformValue(t *testing.T) {
+	raw := dbconfig.NewSchemaConfig("RAW")
+	raw.IsManaged = true
+	raw.DataRetentionTimeInDays = ptr(0)
+	raw.Grants = &dbconfig.SchemaGrants{CreateTableRoles: []string{"TT_WRITER"}}
+
+	warehouse := dbconfig.NewDatabaseConfig("TT_DW")
+	warehouse.Comment = ptr("Data warehouse")
+	warehouse.Grants = &dbconfig.DatabaseGrants{UsageRoles: []string{"TT_READER"}}
+	warehouse.Schemas = []dbconfig.SchemaConfig{raw, dbconfig.NewSchemaConfig("STAGING")}
+
+	value, err := dbconfig.DatabaseConfigs{
+		"warehouse": warehouse,
+		"empty":     dbconfig.NewDatabaseConfig("TT_EMPTY"),
+	}.TerraformValue()
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"warehouse": map[string]interface{}{
+			"name":                        "TT_DW",
+			"comment":                     "Data warehouse",
+			"data_retention_time_in_days": float64(1),
+			"is_transient":                false,
+			"grants": map[string]interface{}{
+				"usage_roles": []interface{}{"TT_READER"},
+			},
+			"schemas": []interface{}{
+				map[string]interface{}{
+					"name":                        "RAW",
+					"is_transient":                false,
+					"is_managed":                  true,
+					"data_retention_time_in_days": float64(0),
+					"grants": map[string]interface{}{
+						"create_table_roles": []interface{}{"TT_WRITER"},
+					},
+				},
+				map[string]interface{}{
+					"name":         "STAGING",
+					"is_transient": false,
+					"is_managed":   false,
+				},
+			},
+		},
+		"empty": map[string]interface{}{
+			"name":                        "TT_EMPTY",
+			"data_retention_time_in_days": float64(1),
+			"is_transient":                false,
+		},
+	}, value)
+}
+
+// TestDatabaseConfigsUnmarshalDefaults verifies decoding a database_configs
+// JSON document applies the optional() defaults to omitted attributes
+func TestDatabaseConfigsUnmarshalDefaults(t *testing.T) {
+	var configs dbconfig.DatabaseConfigs
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"analytics": {"name": "ANALYTICS_DB", "schemas": [{"name": "RAW"}]},
+		"archive": {"name": "ARCHIVE_DB", "data_retention_time_in_days": 0, "is_transient": true}
+	}`), &configs))
+
+	analytics := configs["analytics"]
+	require.Equal(t, "ANALYTICS_DB", analytics.Name)
+	require.Nil(t, analytics.Comment)
+	require.Equal(t, 1, analytics.DataRetentionTimeInDays)
+	require.False(t, analytics.IsTransient)
+	require.Equal(t, []dbconfig.SchemaConfig{dbconfig.NewSchemaConfig("RAW")}, analytics.Schemas)
+	require.Equal(t, 1, analytics.SchemaRetention(analytics.Schemas[0]))
+
+	require.Equal(t, 0, configs["archive"].DataRetentionTimeInDays)
+	require.True(t, configs["archive"].IsTransient)
+}
+
+// TestDatabaseConfigAllSchemas verifies list schemas come first, followed by
+// schemas_by_key entries in key order
+func TestDatabaseConfigAllSchemas(t *testing.T) {
+	cfg := dbconfig.NewDatabaseConfig("ANALYTICS_DB")
+	cfg.Schemas = []dbconfig.SchemaConfig{dbconfig.NewSchemaConfig("RAW")}
+	cfg.SchemasByKey = map[string]dbconfig.SchemaConfig{
+		"staging": dbconfig.NewSchemaConfig("LANDING"),
+		"marts":   dbconfig.NewSchemaConfig("MARTS"),
+	}
+
+	var names []string
+	for _, schema := range cfg.AllSchemas() {
+		names = append(names, schema.Name)
+	}
+	require.Equal(t, []string{"RAW", "MARTS", "LANDING"}, names)
+
+	value, err := dbconfig.DatabaseConfigs{"analytics": cfg}.TerraformValue()
+	require.NoError(t, err)
+	byKey := value["analytics"].(map[string]interface{})["schemas_by_key"]
+	require.Contains(t, byKey, "staging")
+}
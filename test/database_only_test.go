@@ -3,7 +3,6 @@ package test
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"testing"
 	"time"
@@ -11,11 +10,13 @@ import (
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
 )
 
 // TestSingleDatabase tests creating a single database without schemas
 // Property 1: Database Creation Round-Trip
 // Property 3: Configuration Fidelity
+// Property 12: Idempotent Apply
 func TestSingleDatabase(t *testing.T) {
 	t.Parallel()
 
@@ -25,32 +26,34 @@ func TestSingleDatabase(t *testing.T) {
 
 	tfDir := "../examples/database-only"
 
-	databaseConfigs := map[string]interface{}{
-		"test_db": map[string]interface{}{
-			"name":                        dbName,
-			"comment":                     "Terratest single database test",
-			"data_retention_time_in_days": 1,
-			"is_transient":                false,
-			"schemas":                     []interface{}{},
-		},
+	testDb := dbconfig.NewDatabaseConfig(dbName)
+	testDb.Comment = ptr("Terratest single database test")
+	testDb.DataRetentionTimeInDays = 1
+	testDb.IsTransient = false
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"test_db": testDb,
 	}
 
 	tfOptions := &terraform.Options{
 		TerraformDir: tfDir,
 		NoColor:      true,
-		Vars: map[string]interface{}{
-			"database_configs":            databaseConfigs,
-			"snowflake_organization_name": os.Getenv("SNOWFLAKE_ORGANIZATION_NAME"),
-			"snowflake_account_name":      os.Getenv("SNOWFLAKE_ACCOUNT_NAME"),
-			"snowflake_user":              os.Getenv("SNOWFLAKE_USER"),
-			"snowflake_role":              os.Getenv("SNOWFLAKE_ROLE"),
-			"snowflake_private_key":       os.Getenv("SNOWFLAKE_PRIVATE_KEY"),
-		},
+		Vars:         exampleVars(t, databaseConfigs),
+	}
+
+	if planOnly() {
+		plan := initAndPlanJSON(t, tfOptions)
+		requirePlannedDatabase(t, plan, "test_db", dbName, "Terratest single database test", 1, false)
+		require.Empty(t, plannedKeys(plan, "snowflake_schema", "this"))
+		return
 	}
 
 	defer terraform.Destroy(t, tfOptions)
 	terraform.InitAndApply(t, tfOptions)
 
+	// Property 12: Idempotent Apply - a second plan is empty
+	requireIdempotent(t, tfOptions)
+
 	time.Sleep(retrySleep)
 
 	db := openSnowflake(t)
@@ -62,6 +65,8 @@ func TestSingleDatabase(t *testing.T) {
 
 	// Property 3: Configuration Fidelity
 	props := fetchDatabaseProps(t, db, dbName)
-	require.Equal(t, dbName, props.Name)
-	require.Contains(t, props.Comment, "Terratest single database test")
+	requireDatabaseMatchesConfig(t, testDb, props)
+	require.Equal(t, "STANDARD", props.Kind, "Expected a standard database")
+	require.False(t, props.IsShared(), "Expected a database that was not created from a share")
+	require.False(t, props.CreatedOn.IsZero(), "Expected created_on to be reported")
 }
@@ -0,0 +1,119 @@
+// File: test/database_rename_test.go
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+)
+
+// TestDatabaseRename tests that changing a database name renames the existing
+// database, and that its schemas and grants follow it through the state steps
+// in the README
+// Property 1: Database Creation Round-Trip
+// Property 2: Schema Creation Round-Trip
+// Property 5: Grant Fidelity
+// Property 11: Rename In Place
+func TestDatabaseRename(t *testing.T) {
+	t.Parallel()
+
+	retrySleep := 5 * time.Second
+	unique := strings.ToUpper(random.UniqueId())
+	oldName := fmt.Sprintf("TT_ACCOUNTING_%s", unique)
+	newName := fmt.Sprintf("TT_FINANCE_%s", unique)
+	readerRole := fmt.Sprintf("TT_READER_%s", unique)
+
+	tfDir := "../examples/database-with-grants"
+
+	entries := dbconfig.NewSchemaConfig("ENTRIES")
+	entries.Grants = &dbconfig.SchemaGrants{UsageRoles: []string{readerRole}}
+
+	finance := dbconfig.NewDatabaseConfig(oldName)
+	finance.Comment = ptr("Renamed during a re-org")
+	finance.Grants = &dbconfig.DatabaseGrants{
+		UsageRoles:   []string{readerRole},
+		MonitorRoles: []string{readerRole},
+	}
+	finance.Schemas = []dbconfig.SchemaConfig{entries}
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"finance": finance,
+	}
+
+	tfOptions := &terraform.Options{
+		TerraformDir: tfDir,
+		NoColor:      true,
+		Vars:         exampleVars(t, databaseConfigs),
+	}
+
+	if planOnly() {
+		plan := initAndPlanJSON(t, tfOptions)
+		requirePlannedDatabase(t, plan, "finance", oldName, "Renamed during a re-org", 1, false)
+		require.Equal(t, []string{"finance/" + readerRole}, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "database_usage"))
+		require.Equal(t, []string{"finance.ENTRIES/" + readerRole}, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_usage"))
+		return
+	}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	createRoles(t, db, readerRole)
+	defer dropRoles(t, db, readerRole)
+
+	defer terraform.Destroy(t, tfOptions)
+	terraform.InitAndApply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	// Property 1: Database Creation Round-Trip
+	before := fetchDatabaseProps(t, db, oldName)
+	requireDatabaseMatchesConfig(t, finance, before)
+
+	// Property 2: Schema Creation Round-Trip
+	entriesBefore := fetchSchemaProps(t, db, oldName, "ENTRIES")
+	requireSchemaMatchesConfig(t, finance, entries, entriesBefore)
+
+	// Property 5: Grant Fidelity
+	requireGrantsMatchConfig(t, db, finance, dbconfig.AccessRolesConfig{})
+
+	// Property 11: Rename In Place - same key, new name, same object. The
+	// schema leaves state while the database is renamed and is imported back
+	// under the new name, as described in the README.
+	finance.Name = newName
+	databaseConfigs["finance"] = finance
+	tfOptions.Vars = exampleVars(t, databaseConfigs)
+
+	schemaAddress := `module.database.snowflake_schema.this["finance.ENTRIES"]`
+	terraform.RunTerraformCommand(t, tfOptions, "state", "rm", schemaAddress)
+
+	targetOptions := *tfOptions
+	targetOptions.Targets = []string{`module.database.snowflake_database.this["finance"]`}
+	terraform.Apply(t, &targetOptions)
+
+	importArgs := append([]string{"import"}, terraform.FormatTerraformVarsAsArgs(tfOptions.Vars)...)
+	importArgs = append(importArgs, schemaAddress, fmt.Sprintf(`"%s"."%s"`, newName, entries.Name))
+	terraform.RunTerraformCommand(t, tfOptions, importArgs...)
+
+	terraform.Apply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	after := requireDatabaseRenamed(t, db, before, newName)
+	requireDatabaseMatchesConfig(t, finance, after)
+
+	// The schema moved with the database instead of being recreated
+	entriesAfter := fetchSchemaProps(t, db, newName, "ENTRIES")
+	require.True(t, entriesBefore.CreatedOn.Equal(entriesAfter.CreatedOn),
+		"Expected schema ENTRIES to keep created_on %s after the rename, got %s", entriesBefore.CreatedOn, entriesAfter.CreatedOn)
+	requireSchemaMatchesConfig(t, finance, entries, entriesAfter)
+
+	// The grants follow the renamed database and schema
+	requireGrantsMatchConfig(t, db, finance, dbconfig.AccessRolesConfig{})
+	requireIdempotent(t, tfOptions)
+}
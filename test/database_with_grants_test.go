@@ -0,0 +1,223 @@
+// File: test/database_with_grants_test.go
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+)
+
+// TestDatabaseWithGrants tests every database and schema grants list and
+// schema future grants
+// Property 1: Database Creation Round-Trip
+// Property 2: Schema Creation Round-Trip
+// Property 5: Grant Fidelity
+// Property 6: Grant Revocation
+func TestDatabaseWithGrants(t *testing.T) {
+	t.Parallel()
+
+	retrySleep := 5 * time.Second
+	unique := strings.ToUpper(random.UniqueId())
+	dbName := fmt.Sprintf("TT_GRANTS_%s", unique)
+	schemaName := fmt.Sprintf("TT_RAW_%s", unique)
+	readerRole := fmt.Sprintf("TT_READER_%s", unique)
+	writerRole := fmt.Sprintf("TT_WRITER_%s", unique)
+	loaderRole := fmt.Sprintf("TT_LOADER_%s", unique)
+
+	tfDir := "../examples/database-with-grants"
+
+	schema := dbconfig.NewSchemaConfig(schemaName)
+	schema.Comment = ptr("Terratest grants schema")
+	schema.Grants = &dbconfig.SchemaGrants{
+		UsageRoles:                  []string{readerRole, writerRole, loaderRole},
+		CreateFileFormatRoles:       []string{loaderRole},
+		CreateStageRoles:            []string{loaderRole},
+		CreateTableRoles:            []string{writerRole, loaderRole},
+		CreatePipeRoles:             []string{loaderRole},
+		CreateViewRoles:             []string{writerRole, loaderRole},
+		CreateMaterializedViewRoles: []string{loaderRole},
+		CreateSequenceRoles:         []string{loaderRole},
+		CreateFunctionRoles:         []string{loaderRole},
+		CreateProcedureRoles:        []string{loaderRole},
+		CreateStreamRoles:           []string{loaderRole},
+		CreateTaskRoles:             []string{loaderRole},
+		CreateDynamicTableRoles:     []string{loaderRole},
+		MonitorRoles:                []string{readerRole, loaderRole},
+		Privileges: map[string][]string{
+			"CREATE TAG":   {writerRole},
+			"CREATE ALERT": {loaderRole},
+		},
+	}
+	schema.FutureGrants = map[string]map[string][]string{
+		"TABLES": {"SELECT": {readerRole}, "INSERT": {writerRole}},
+		"VIEWS":  {"SELECT": {readerRole}},
+	}
+	schema.AllObjectsGrants = map[string]map[string][]string{
+		"TABLES": {"SELECT": {readerRole}},
+	}
+
+	app := dbconfig.NewDatabaseConfig(dbName)
+	app.Comment = ptr("Terratest grants database")
+	app.Grants = &dbconfig.DatabaseGrants{
+		UsageRoles:              []string{readerRole, writerRole, loaderRole},
+		MonitorRoles:            []string{loaderRole},
+		CreateSchemaRoles:       []string{writerRole, loaderRole},
+		ModifyRoles:             []string{loaderRole},
+		CreateDatabaseRoleRoles: []string{loaderRole},
+		Privileges:              map[string][]string{"MONITOR": {readerRole}},
+	}
+	app.Schemas = []dbconfig.SchemaConfig{schema}
+	app.DatabaseRoles = map[string]dbconfig.DatabaseRoleConfig{
+		"TT_DOMAIN_READ": {
+			Comment:               ptr("Terratest domain read access"),
+			GrantedToAccountRoles: []string{readerRole},
+			DatabasePrivileges:    []string{"USAGE"},
+			SchemaPrivileges:      map[string][]string{schemaName: {"USAGE", "MONITOR"}},
+		},
+	}
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"app": app,
+	}
+
+	tfOptions := &terraform.Options{
+		TerraformDir: tfDir,
+		NoColor:      true,
+		Vars:         exampleVars(t, databaseConfigs),
+	}
+
+	if planOnly() {
+		plan := initAndPlanJSON(t, tfOptions)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "database_usage"), 3)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "database_monitor"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "database_create_schema"), 2)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "database_modify"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "database_create_database_role"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_usage"), 3)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_file_format"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_stage"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_table"), 2)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_pipe"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_view"), 2)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_materialized_view"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_sequence"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_function"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_procedure"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_stream"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_task"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_dynamic_table"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_monitor"), 2)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "database_privileges"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_privileges"), 2)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_future"), 3)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_all_objects"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_database_role", "this"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_database_role", "database"), 1)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_database_role", "schema"), 2)
+		require.Len(t, plannedKeys(plan, "snowflake_grant_database_role", "to_account_role"), 1)
+		return
+	}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	createRoles(t, db, readerRole, writerRole, loaderRole)
+	defer dropRoles(t, db, readerRole, writerRole, loaderRole)
+
+	defer terraform.Destroy(t, tfOptions)
+	terraform.InitAndApply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	// Property 1: Database Creation Round-Trip
+	require.True(t, databaseExists(t, db, dbName), "Expected database %q to exist", dbName)
+
+	// Property 2: Schema Creation Round-Trip
+	require.True(t, schemaExists(t, db, dbName, schemaName), "Expected schema %q in database %q", schemaName, dbName)
+
+	// Property 5: Grant Fidelity - every privilege is held by exactly the listed roles
+	requireGrantsMatchConfig(t, db, app, dbconfig.AccessRolesConfig{})
+	require.False(t, hasPrivilege(fetchSchemaGrants(t, db, dbName, schemaName, readerRole), "CREATE TABLE"),
+		"Expected reader role to have no CREATE TABLE on schema")
+
+	// Database roles hold their privileges and are granted to account roles
+	requireDatabaseRolesMatchConfig(t, db, app)
+	domainGrants := fetchSchemaGrantsToDatabaseRole(t, db, dbName, schemaName, "TT_DOMAIN_READ")
+	require.True(t, hasPrivilege(domainGrants, "MONITOR"), "Expected MONITOR on schema for database role")
+	require.True(t, domainGrants[0].IsDatabaseRole(), "Expected the grantee to be reported as a database role")
+
+	// The loader role is listed for every database privilege list
+	loaderDbGrants := fetchDatabaseGrants(t, db, dbName, loaderRole)
+	for _, privilege := range []string{"USAGE", "MONITOR", "CREATE SCHEMA", "MODIFY", "CREATE DATABASE ROLE"} {
+		require.True(t, hasPrivilege(loaderDbGrants, privilege), "Expected %s on database for loader role", privilege)
+	}
+	writerDbGrants := fetchDatabaseGrants(t, db, dbName, writerRole)
+	require.True(t, hasPrivilege(writerDbGrants, "CREATE SCHEMA"), "Expected CREATE SCHEMA on database for writer role")
+	require.False(t, hasPrivilege(writerDbGrants, "MODIFY"), "Expected writer role to have no MODIFY on database")
+
+	// The loader role is listed for every schema privilege the module supports
+	loaderGrants := fetchSchemaGrants(t, db, dbName, schemaName, loaderRole)
+	for _, privilege := range []string{
+		"USAGE", "CREATE FILE FORMAT", "CREATE STAGE", "CREATE TABLE", "CREATE PIPE",
+		"CREATE VIEW", "CREATE MATERIALIZED VIEW", "CREATE SEQUENCE", "CREATE FUNCTION",
+		"CREATE PROCEDURE", "CREATE STREAM", "CREATE TASK", "CREATE DYNAMIC TABLE", "MONITOR",
+	} {
+		require.True(t, hasPrivilege(loaderGrants, privilege), "Expected %s on schema for loader role", privilege)
+	}
+	readerGrants := fetchSchemaGrants(t, db, dbName, schemaName, readerRole)
+	require.True(t, hasPrivilege(readerGrants, "MONITOR"), "Expected MONITOR on schema for reader role")
+	require.False(t, hasPrivilege(readerGrants, "CREATE VIEW"), "Expected reader role to have no CREATE VIEW on schema")
+
+	// Future grants cover tables and views created after the apply
+	readerFuture := fetchSchemaFutureGrants(t, db, dbName, schemaName, readerRole)
+	require.True(t, hasFutureGrant(readerFuture, "TABLES", "SELECT"), "Expected SELECT on future tables for reader role")
+	require.True(t, hasFutureGrant(readerFuture, "VIEWS", "SELECT"), "Expected SELECT on future views for reader role")
+	require.False(t, hasFutureGrant(readerFuture, "TABLES", "INSERT"), "Expected reader role to have no INSERT on future tables")
+
+	// A table created outside Terraform is covered once all_objects_grants
+	// lists a new privilege for it
+	legacyTable := fmt.Sprintf("%s.%s.TT_LEGACY", dbName, schemaName)
+	_, err := db.Exec(fmt.Sprintf("CREATE TABLE %s (ID INT);", legacyTable))
+	require.NoError(t, err, "Failed to create table %s", legacyTable)
+	schema.AllObjectsGrants["TABLES"]["INSERT"] = []string{loaderRole}
+
+	// Property 6: Grant Revocation - removing a role from a list revokes the privilege
+	schema.Grants.CreateTableRoles = []string{loaderRole}
+	app.Grants = &dbconfig.DatabaseGrants{
+		UsageRoles:              []string{writerRole, loaderRole},
+		MonitorRoles:            []string{loaderRole},
+		CreateSchemaRoles:       []string{loaderRole},
+		ModifyRoles:             []string{loaderRole},
+		CreateDatabaseRoleRoles: []string{loaderRole},
+	}
+	delete(schema.Grants.Privileges, "CREATE ALERT")
+	delete(schema.FutureGrants, "VIEWS")
+	app.Schemas = []dbconfig.SchemaConfig{schema}
+	databaseConfigs["app"] = app
+
+	tfOptions.Vars = exampleVars(t, databaseConfigs)
+	terraform.Apply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	requireGrantsMatchConfig(t, db, app, dbconfig.AccessRolesConfig{})
+	requireAllObjectsGranted(t, db, app, schema)
+	require.False(t, hasPrivilege(fetchSchemaGrants(t, db, dbName, schemaName, writerRole), "CREATE TABLE"),
+		"Expected CREATE TABLE to be revoked from writer role")
+	require.False(t, hasPrivilege(fetchDatabaseGrants(t, db, dbName, readerRole), "USAGE"),
+		"Expected database USAGE to be revoked from reader role")
+	require.False(t, hasPrivilege(fetchDatabaseGrants(t, db, dbName, writerRole), "CREATE SCHEMA"),
+		"Expected database CREATE SCHEMA to be revoked from writer role")
+	require.False(t, hasPrivilege(fetchDatabaseGrants(t, db, dbName, readerRole), "MONITOR"),
+		"Expected database MONITOR from the privileges map to be revoked from reader role")
+	require.False(t, hasPrivilege(fetchSchemaGrants(t, db, dbName, schemaName, loaderRole), "CREATE ALERT"),
+		"Expected CREATE ALERT from the privileges map to be revoked from loader role")
+	require.False(t, hasFutureGrant(fetchSchemaFutureGrants(t, db, dbName, schemaName, readerRole), "VIEWS", "SELECT"),
+		"Expected SELECT on future views to be revoked from reader role")
+}
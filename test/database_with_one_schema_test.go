@@ -3,7 +3,6 @@ package test
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"testing"
 	"time"
@@ -11,12 +10,14 @@ import (
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
 )
 
 // TestDatabaseWithSchema tests creating a database with one schema
 // Property 1: Database Creation Round-Trip
 // Property 2: Schema Creation Round-Trip
 // Property 3: Configuration Fidelity
+// Property 12: Idempotent Apply
 func TestDatabaseWithSchema(t *testing.T) {
 	t.Parallel()
 
@@ -27,36 +28,37 @@ func TestDatabaseWithSchema(t *testing.T) {
 
 	tfDir := "../examples/database-with-one-schema"
 
-	databaseConfigs := map[string]interface{}{
-		"app": map[string]interface{}{
-			"name":    dbName,
-			"comment": "Terratest database with schema test",
-			"schemas": []interface{}{
-				map[string]interface{}{
-					"name":       schemaName,
-					"comment":    "Terratest schema",
-					"is_managed": true,
-				},
-			},
-		},
+	schema := dbconfig.NewSchemaConfig(schemaName)
+	schema.Comment = ptr("Terratest schema")
+	schema.IsManaged = true
+
+	app := dbconfig.NewDatabaseConfig(dbName)
+	app.Comment = ptr("Terratest database with schema test")
+	app.Schemas = []dbconfig.SchemaConfig{schema}
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"app": app,
 	}
 
 	tfOptions := &terraform.Options{
 		TerraformDir: tfDir,
 		NoColor:      true,
-		Vars: map[string]interface{}{
-			"database_configs":            databaseConfigs,
-			"snowflake_organization_name": os.Getenv("SNOWFLAKE_ORGANIZATION_NAME"),
-			"snowflake_account_name":      os.Getenv("SNOWFLAKE_ACCOUNT_NAME"),
-			"snowflake_user":              os.Getenv("SNOWFLAKE_USER"),
-			"snowflake_role":              os.Getenv("SNOWFLAKE_ROLE"),
-			"snowflake_private_key":       os.Getenv("SNOWFLAKE_PRIVATE_KEY"),
-		},
+		Vars:         exampleVars(t, databaseConfigs),
+	}
+
+	if planOnly() {
+		plan := initAndPlanJSON(t, tfOptions)
+		requirePlannedDatabase(t, plan, "app", dbName, "Terratest database with schema test", 1, false)
+		requirePlannedSchema(t, plan, "app."+schemaName, schemaName, "Terratest schema", false, true)
+		return
 	}
 
 	defer terraform.Destroy(t, tfOptions)
 	terraform.InitAndApply(t, tfOptions)
 
+	// Property 12: Idempotent Apply - a second plan is empty
+	requireIdempotent(t, tfOptions)
+
 	time.Sleep(retrySleep)
 
 	db := openSnowflake(t)
@@ -72,12 +74,15 @@ func TestDatabaseWithSchema(t *testing.T) {
 
 	// Property 3: Configuration Fidelity
 	dbProps := fetchDatabaseProps(t, db, dbName)
-	require.Equal(t, dbName, dbProps.Name)
-	require.Contains(t, dbProps.Comment, "Terratest database with schema test")
+	requireDatabaseMatchesConfig(t, app, dbProps)
 
 	schemaProps := fetchSchemaProps(t, db, dbName, schemaName)
-	require.Equal(t, schemaName, schemaProps.Name)
-	require.Equal(t, dbName, schemaProps.DatabaseName)
-	require.Contains(t, schemaProps.Comment, "Terratest schema")
+	requireSchemaMatchesConfig(t, app, schema, schemaProps)
 	require.True(t, schemaProps.IsManagedAccess, "Expected schema to have managed access enabled")
+
+	// A schema without its own retention inherits the database parameter
+	schemaParams := fetchSchemaParameters(t, db, dbName, schemaName)
+	require.False(t, schemaParams["DATA_RETENTION_TIME_IN_DAYS"].SetOn("SCHEMA"), "Expected schema retention to be inherited")
+	requireParameter(t, schemaParams, "DATA_RETENTION_TIME_IN_DAYS", fmt.Sprint(app.DataRetentionTimeInDays),
+		fetchDatabaseParameters(t, db, dbName)["DATA_RETENTION_TIME_IN_DAYS"].Level)
 }
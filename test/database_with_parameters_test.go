@@ -0,0 +1,115 @@
+// File: test/database_with_parameters_test.go
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+)
+
+// TestDatabaseWithParameters tests Snowflake parameters set on a database,
+// including log and trace levels and the event table, and inherited or
+// overridden by its schemas
+// Property 1: Database Creation Round-Trip
+// Property 2: Schema Creation Round-Trip
+// Property 9: Parameter Fidelity
+func TestDatabaseWithParameters(t *testing.T) {
+	t.Parallel()
+
+	retrySleep := 5 * time.Second
+	unique := strings.ToUpper(random.UniqueId())
+	dbName := fmt.Sprintf("TT_PARAMS_%s", unique)
+	eventsDbName := fmt.Sprintf("TT_EVENTS_%s", unique)
+	eventTable := eventsDbName + ".PUBLIC.EVENTS"
+
+	tfDir := "../examples/database-with-parameters"
+
+	raw := dbconfig.NewSchemaConfig("RAW")
+
+	iceberg := dbconfig.NewSchemaConfig("ICEBERG")
+	iceberg.MaxDataExtensionTimeInDays = ptr(7)
+	iceberg.DefaultDDLCollation = ptr("en-cs")
+	iceberg.ReplaceInvalidCharacters = ptr(true)
+	iceberg.StorageSerializationPolicy = ptr("COMPATIBLE")
+	iceberg.LogLevel = ptr("DEBUG")
+	iceberg.TraceLevel = ptr("ALWAYS")
+
+	lake := dbconfig.NewDatabaseConfig(dbName)
+	lake.DataRetentionTimeInDays = 3
+	lake.MaxDataExtensionTimeInDays = ptr(30)
+	lake.DefaultDDLCollation = ptr("en-ci")
+	lake.StorageSerializationPolicy = ptr("OPTIMIZED")
+	lake.LogLevel = ptr("WARN")
+	lake.TraceLevel = ptr("ON_EVENT")
+	lake.EventTable = ptr(eventTable)
+	lake.Schemas = []dbconfig.SchemaConfig{raw, iceberg}
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"lake": lake,
+	}
+
+	tfOptions := &terraform.Options{
+		TerraformDir: tfDir,
+		NoColor:      true,
+		Vars:         exampleVars(t, databaseConfigs),
+	}
+
+	if planOnly() {
+		plan := initAndPlanJSON(t, tfOptions)
+		requirePlannedDatabase(t, plan, "lake", dbName, "", 3, false)
+		database := requirePlannedResource(t, plan, "snowflake_database", "this", "lake")
+		require.Equal(t, 30, getInt(database["max_data_extension_time_in_days"]))
+		require.Equal(t, "en-ci", database["default_ddl_collation"])
+		schema := requirePlannedResource(t, plan, "snowflake_schema", "this", "lake.ICEBERG")
+		require.Equal(t, "COMPATIBLE", schema["storage_serialization_policy"])
+		require.Equal(t, true, schema["replace_invalid_characters"])
+		require.Equal(t, "DEBUG", schema["log_level"])
+		eventParam := requirePlannedResource(t, plan, "snowflake_object_parameter", "database_event_table", "lake")
+		require.Equal(t, "EVENT_TABLE", eventParam["key"])
+		require.Equal(t, eventTable, eventParam["value"])
+		requirePreviewFeatureEnabled(t, tfOptions, plan, "snowflake_object_parameter", "snowflake_object_parameter_resource")
+		return
+	}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	// The event table lives outside the module so it exists before the apply
+	_, err := db.Exec(fmt.Sprintf("CREATE DATABASE %s;", eventsDbName))
+	require.NoError(t, err, "Failed to create database %s", eventsDbName)
+	defer func() { _, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s;", eventsDbName)) }()
+	_, err = db.Exec(fmt.Sprintf("CREATE EVENT TABLE %s;", eventTable))
+	require.NoError(t, err, "Failed to create event table %s", eventTable)
+
+	defer terraform.Destroy(t, tfOptions)
+	terraform.InitAndApply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	// Property 1: Database Creation Round-Trip
+	require.True(t, databaseExists(t, db, dbName), "Expected database %q to exist", dbName)
+
+	// Property 2: Schema Creation Round-Trip
+	for _, schema := range lake.Schemas {
+		require.True(t, schemaExists(t, db, dbName, schema.Name), "Expected schema %q in database %q", schema.Name, dbName)
+	}
+
+	// Property 9: Parameter Fidelity - set on the object or inherited from the database
+	requireParametersMatchConfig(t, db, lake)
+	rawParams := fetchSchemaParameters(t, db, dbName, "RAW")
+	require.False(t, rawParams["MAX_DATA_EXTENSION_TIME_IN_DAYS"].SetOn("SCHEMA"), "Expected RAW to inherit max_data_extension_time_in_days")
+	requireParameter(t, rawParams, "DATA_RETENTION_TIME_IN_DAYS", "3", "DATABASE")
+
+	// Log and trace levels are inherited or overridden at the schema level
+	requireParameter(t, rawParams, "LOG_LEVEL", "WARN", "DATABASE")
+	requireParameter(t, rawParams, "TRACE_LEVEL", "ON_EVENT", "DATABASE")
+	icebergParams := fetchSchemaParameters(t, db, dbName, "ICEBERG")
+	requireParameter(t, icebergParams, "LOG_LEVEL", "DEBUG", "SCHEMA")
+	requireParameter(t, icebergParams, "TRACE_LEVEL", "ALWAYS", "SCHEMA")
+}
@@ -0,0 +1,101 @@
+// File: test/database_with_tags_test.go
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+)
+
+// TestDatabaseWithTags tests tag associations on a database and its schemas
+// Property 1: Database Creation Round-Trip
+// Property 2: Schema Creation Round-Trip
+// Property 10: Tag Fidelity
+func TestDatabaseWithTags(t *testing.T) {
+	t.Parallel()
+
+	retrySleep := 5 * time.Second
+	unique := strings.ToUpper(random.UniqueId())
+	dbName := fmt.Sprintf("TT_TAGGED_%s", unique)
+	govDbName := fmt.Sprintf("TT_GOV_%s", unique)
+	costCenterTag := govDbName + ".TAGS.COST_CENTER"
+	sensitivityTag := govDbName + ".TAGS.SENSITIVITY"
+
+	tfDir := "../examples/database-with-tags"
+
+	ledger := dbconfig.NewSchemaConfig("LEDGER")
+	ledger.Tags = map[string]string{sensitivityTag: "confidential"}
+
+	finance := dbconfig.NewDatabaseConfig(dbName)
+	finance.Tags = map[string]string{
+		costCenterTag:  "FIN-001",
+		sensitivityTag: "internal",
+	}
+	finance.Schemas = []dbconfig.SchemaConfig{dbconfig.NewSchemaConfig("REPORTS"), ledger}
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"finance": finance,
+	}
+
+	tfOptions := &terraform.Options{
+		TerraformDir: tfDir,
+		NoColor:      true,
+		Vars:         exampleVars(t, databaseConfigs),
+	}
+
+	if planOnly() {
+		plan := initAndPlanJSON(t, tfOptions)
+		require.Equal(t, []string{"finance/" + costCenterTag, "finance/" + sensitivityTag}, plannedKeys(plan, "snowflake_tag_association", "database"))
+		require.Equal(t, []string{"finance.LEDGER/" + sensitivityTag}, plannedKeys(plan, "snowflake_tag_association", "schema"))
+		return
+	}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	// Tags are created outside the module, as they usually live in a
+	// governance database shared by many databases
+	for _, stmt := range []string{
+		fmt.Sprintf("CREATE DATABASE %s;", govDbName),
+		fmt.Sprintf("CREATE SCHEMA %s.TAGS;", govDbName),
+		fmt.Sprintf("CREATE TAG %s;", costCenterTag),
+		fmt.Sprintf("CREATE TAG %s;", sensitivityTag),
+	} {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err, "Failed to run %s", stmt)
+	}
+	defer func() { _, _ = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s;", govDbName)) }()
+
+	defer terraform.Destroy(t, tfOptions)
+	terraform.InitAndApply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	// Property 1: Database Creation Round-Trip
+	require.True(t, databaseExists(t, db, dbName), "Expected database %q to exist", dbName)
+
+	// Property 2: Schema Creation Round-Trip
+	require.True(t, schemaExists(t, db, dbName, "LEDGER"), "Expected schema LEDGER in database %q", dbName)
+
+	// Property 10: Tag Fidelity - each object carries exactly its configured tags
+	requireTagsMatchConfig(t, db, finance)
+	reports := fetchTagReferences(t, db, dbName, dbName+".REPORTS", "SCHEMA")
+	require.Len(t, reports, 2, "Expected REPORTS to inherit both database tags")
+
+	// Changing a value and dropping a tag updates the associations
+	finance.Tags = map[string]string{costCenterTag: "FIN-002"}
+	databaseConfigs["finance"] = finance
+
+	tfOptions.Vars = exampleVars(t, databaseConfigs)
+	terraform.Apply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	requireTagsMatchConfig(t, db, finance)
+}
@@ -3,7 +3,6 @@ package test
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +10,7 @@ import (
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
 )
 
 // TestDatabaseWithMultipleSchemas tests creating a single database with multiple schemas
@@ -18,6 +18,7 @@ import (
 // Property 2: Schema Creation Round-Trip
 // Property 3: Configuration Fidelity
 // Property 4: Transient Resource Handling
+// Property 12: Idempotent Apply
 func TestDatabaseWithMultipleSchemas(t *testing.T) {
 	t.Parallel()
 
@@ -31,47 +32,48 @@ func TestDatabaseWithMultipleSchemas(t *testing.T) {
 
 	tfDir := "../examples/databases-with-multiple-schemas"
 
-	databaseConfigs := map[string]interface{}{
-		"datawarehouse": map[string]interface{}{
-			"name":                        dbName,
-			"comment":                     "Terratest data warehouse",
-			"data_retention_time_in_days": 7,
-			"schemas": []interface{}{
-				map[string]interface{}{
-					"name":       rawSchemaName,
-					"comment":    "Raw ingested data",
-					"is_managed": false,
-				},
-				map[string]interface{}{
-					"name":         stagingSchemaName,
-					"comment":      "Data transformation staging area",
-					"is_transient": true,
-				},
-				map[string]interface{}{
-					"name":       curatedSchemaName,
-					"comment":    "Curated business data",
-					"is_managed": true,
-				},
-			},
-		},
+	rawSchema := dbconfig.NewSchemaConfig(rawSchemaName)
+	rawSchema.Comment = ptr("Raw ingested data")
+	rawSchema.IsManaged = false
+
+	stagingSchema := dbconfig.NewSchemaConfig(stagingSchemaName)
+	stagingSchema.Comment = ptr("Data transformation staging area")
+	stagingSchema.IsTransient = true
+
+	curatedSchema := dbconfig.NewSchemaConfig(curatedSchemaName)
+	curatedSchema.Comment = ptr("Curated business data")
+	curatedSchema.IsManaged = true
+
+	datawarehouse := dbconfig.NewDatabaseConfig(dbName)
+	datawarehouse.Comment = ptr("Terratest data warehouse")
+	datawarehouse.DataRetentionTimeInDays = 7
+	datawarehouse.Schemas = []dbconfig.SchemaConfig{rawSchema, stagingSchema, curatedSchema}
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"datawarehouse": datawarehouse,
 	}
 
 	tfOptions := &terraform.Options{
 		TerraformDir: tfDir,
 		NoColor:      true,
-		Vars: map[string]interface{}{
-			"database_configs":            databaseConfigs,
-			"snowflake_organization_name": os.Getenv("SNOWFLAKE_ORGANIZATION_NAME"),
-			"snowflake_account_name":      os.Getenv("SNOWFLAKE_ACCOUNT_NAME"),
-			"snowflake_user":              os.Getenv("SNOWFLAKE_USER"),
-			"snowflake_role":              os.Getenv("SNOWFLAKE_ROLE"),
-			"snowflake_private_key":       os.Getenv("SNOWFLAKE_PRIVATE_KEY"),
-		},
+		Vars:         exampleVars(t, databaseConfigs),
+	}
+
+	if planOnly() {
+		plan := initAndPlanJSON(t, tfOptions)
+		requirePlannedDatabase(t, plan, "datawarehouse", dbName, "Terratest data warehouse", 7, false)
+		requirePlannedSchema(t, plan, "datawarehouse."+rawSchemaName, rawSchemaName, "Raw ingested data", false, false)
+		requirePlannedSchema(t, plan, "datawarehouse."+stagingSchemaName, stagingSchemaName, "Data transformation staging area", true, false)
+		requirePlannedSchema(t, plan, "datawarehouse."+curatedSchemaName, curatedSchemaName, "Curated business data", false, true)
+		return
 	}
 
 	defer terraform.Destroy(t, tfOptions)
 	terraform.InitAndApply(t, tfOptions)
 
+	// Property 12: Idempotent Apply - a second plan is empty
+	requireIdempotent(t, tfOptions)
+
 	time.Sleep(retrySleep)
 
 	db := openSnowflake(t)
@@ -87,10 +89,18 @@ func TestDatabaseWithMultipleSchemas(t *testing.T) {
 
 	// Property 3: Configuration Fidelity - verify database properties
 	dbProps := fetchDatabaseProps(t, db, dbName)
-	require.Equal(t, dbName, dbProps.Name)
-	require.Contains(t, dbProps.Comment, "Terratest data warehouse")
+	requireDatabaseMatchesConfig(t, datawarehouse, dbProps)
+
+	// Verify every schema, including retention inherited from the database
+	for _, schema := range datawarehouse.Schemas {
+		requireSchemaMatchesConfig(t, datawarehouse, schema, fetchSchemaProps(t, db, dbName, schema.Name))
+	}
 
 	// Verify curated schema has managed access
 	curatedProps := fetchSchemaProps(t, db, dbName, curatedSchemaName)
 	require.True(t, curatedProps.IsManagedAccess, "Expected curated schema to have managed access enabled")
+
+	// Property 4: Transient Resource Handling - verify transient schema
+	stagingProps := fetchSchemaProps(t, db, dbName, stagingSchemaName)
+	require.True(t, stagingProps.IsTransient, "Expected staging schema to be transient")
 }
@@ -0,0 +1,500 @@
+// File: test/dbconfig/dbconfig.go
+
+// Package dbconfig models the database_configs and access_roles variables of
+// the module as Go types, so tests and tools can build the value passed to
+// -var and work out the objects and grants the module should create from it.
+package dbconfig
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DatabaseConfigs mirrors the database_configs variable in variables.tf
+type DatabaseConfigs map[string]DatabaseConfig
+
+// DatabaseConfig mirrors a single database_configs entry. Use
+// NewDatabaseConfig so unset fields carry the optional() defaults.
+type DatabaseConfig struct {
+	Name                    string          `json:"name"`
+	Comment                 *string         `json:"comment,omitempty"`
+	DataRetentionTimeInDays int             `json:"data_retention_time_in_days"`
+	IsTransient             bool            `json:"is_transient"`
+	Grants                  *DatabaseGrants `json:"grants,omitempty"`
+	Schemas                 []SchemaConfig  `json:"schemas,omitempty"`
+
+	// SchemasByKey declares schemas by a stable key instead of their name,
+	// so changing Name renames the schema in place
+	SchemasByKey map[string]SchemaConfig `json:"schemas_by_key,omitempty"`
+
+	// OwnerRole takes ownership of the database; nil keeps the creating role.
+	// An empty OwnerOutboundPrivileges leaves the module default, COPY.
+	OwnerRole               *string `json:"owner_role,omitempty"`
+	OwnerOutboundPrivileges string  `json:"owner_outbound_privileges,omitempty"`
+
+	ObjectParameters
+
+	// EventTable is the fully qualified event table for logs and traces
+	EventTable *string `json:"event_table,omitempty"`
+
+	// Tags maps a fully qualified tag name to the value set on the database
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// DatabaseRoles is keyed by database role name
+	DatabaseRoles map[string]DatabaseRoleConfig `json:"database_roles,omitempty"`
+}
+
+// DatabaseGrants mirrors the database-level grants object
+type DatabaseGrants struct {
+	UsageRoles              []string `json:"usage_roles,omitempty"`
+	MonitorRoles            []string `json:"monitor_roles,omitempty"`
+	CreateSchemaRoles       []string `json:"create_schema_roles,omitempty"`
+	ModifyRoles             []string `json:"modify_roles,omitempty"`
+	CreateDatabaseRoleRoles []string `json:"create_database_role_roles,omitempty"`
+
+	// Privileges is the generic privilege name → roles form
+	Privileges map[string][]string `json:"privileges,omitempty"`
+}
+
+// DatabaseRoleConfig mirrors a database_roles entry. SchemaPrivileges maps a
+// schema name in the same database to the privileges granted on it.
+type DatabaseRoleConfig struct {
+	Comment               *string             `json:"comment,omitempty"`
+	GrantedToAccountRoles []string            `json:"granted_to_account_roles,omitempty"`
+	DatabasePrivileges    []string            `json:"database_privileges,omitempty"`
+	SchemaPrivileges      map[string][]string `json:"schema_privileges,omitempty"`
+}
+
+// SchemaConfig mirrors a single schemas entry. Use NewSchemaConfig so unset
+// fields carry the optional() defaults.
+type SchemaConfig struct {
+	Name                    string        `json:"name"`
+	Comment                 *string       `json:"comment,omitempty"`
+	IsTransient             bool          `json:"is_transient"`
+	IsManaged               bool          `json:"is_managed"`
+	DataRetentionTimeInDays *int          `json:"data_retention_time_in_days,omitempty"`
+	Grants                  *SchemaGrants `json:"grants,omitempty"`
+
+	// OwnerRole takes ownership of the schema; nil keeps the creating role.
+	// An empty OwnerOutboundPrivileges leaves the module default, COPY.
+	OwnerRole               *string `json:"owner_role,omitempty"`
+	OwnerOutboundPrivileges string  `json:"owner_outbound_privileges,omitempty"`
+
+	ObjectParameters
+
+	// Tags maps a fully qualified tag name to the value set on the schema
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// FutureGrants maps a plural object type (e.g. TABLES) to privilege → roles
+	FutureGrants map[string]map[string][]string `json:"future_grants,omitempty"`
+
+	// AllObjectsGrants has the same shape and covers objects that already exist
+	AllObjectsGrants map[string]map[string][]string `json:"all_objects_grants,omitempty"`
+
+	AccessRoles *SchemaAccessRoles `json:"access_roles,omitempty"`
+}
+
+// ObjectParameters mirrors the Snowflake parameters both databases and schemas
+// accept. Nil fields are left unset so the object inherits them.
+type ObjectParameters struct {
+	MaxDataExtensionTimeInDays *int    `json:"max_data_extension_time_in_days,omitempty"`
+	DefaultDDLCollation        *string `json:"default_ddl_collation,omitempty"`
+	ExternalVolume             *string `json:"external_volume,omitempty"`
+	Catalog                    *string `json:"catalog,omitempty"`
+	ReplaceInvalidCharacters   *bool   `json:"replace_invalid_characters,omitempty"`
+	StorageSerializationPolicy *string `json:"storage_serialization_policy,omitempty"`
+	LogLevel                   *string `json:"log_level,omitempty"`
+	TraceLevel                 *string `json:"trace_level,omitempty"`
+}
+
+// Values returns the configured parameters keyed by their SHOW PARAMETERS
+// name, with values rendered the way Snowflake reports them
+func (p ObjectParameters) Values() map[string]string {
+	values := map[string]string{}
+	if p.MaxDataExtensionTimeInDays != nil {
+		values["MAX_DATA_EXTENSION_TIME_IN_DAYS"] = strconv.Itoa(*p.MaxDataExtensionTimeInDays)
+	}
+	if p.DefaultDDLCollation != nil {
+		values["DEFAULT_DDL_COLLATION"] = *p.DefaultDDLCollation
+	}
+	if p.ExternalVolume != nil {
+		values["EXTERNAL_VOLUME"] = *p.ExternalVolume
+	}
+	if p.Catalog != nil {
+		values["CATALOG"] = *p.Catalog
+	}
+	if p.ReplaceInvalidCharacters != nil {
+		values["REPLACE_INVALID_CHARACTERS"] = strconv.FormatBool(*p.ReplaceInvalidCharacters)
+	}
+	if p.StorageSerializationPolicy != nil {
+		values["STORAGE_SERIALIZATION_POLICY"] = strings.ToUpper(*p.StorageSerializationPolicy)
+	}
+	if p.LogLevel != nil {
+		values["LOG_LEVEL"] = strings.ToUpper(*p.LogLevel)
+	}
+	if p.TraceLevel != nil {
+		values["TRACE_LEVEL"] = strings.ToUpper(*p.TraceLevel)
+	}
+	return values
+}
+
+// SchemaAccessRoles mirrors the schema-level access_roles object: whether the
+// schema gets generated roles and which roles each level is granted to
+type SchemaAccessRoles struct {
+	Enabled             *bool    `json:"enabled,omitempty"`
+	ROGrantedToRoles    []string `json:"ro_granted_to_roles,omitempty"`
+	RWGrantedToRoles    []string `json:"rw_granted_to_roles,omitempty"`
+	OwnerGrantedToRoles []string `json:"owner_granted_to_roles,omitempty"`
+}
+
+// Members returns the roles a generated access level is granted to
+func (a *SchemaAccessRoles) Members(level string) []string {
+	if a == nil {
+		return nil
+	}
+	switch level {
+	case "RO":
+		return a.ROGrantedToRoles
+	case "RW":
+		return a.RWGrantedToRoles
+	case "OWNER":
+		return a.OwnerGrantedToRoles
+	}
+	return nil
+}
+
+// AccessRolesConfig mirrors the access_roles variable in variables.tf
+type AccessRolesConfig struct {
+	Enabled      bool    `json:"enabled"`
+	NameTemplate string  `json:"name_template,omitempty"`
+	ParentRole   *string `json:"parent_role,omitempty"`
+}
+
+// AccessRoleLevels are the generated access levels, lowest first. Each level
+// is granted to the next one.
+var AccessRoleLevels = []string{"RO", "RW", "OWNER"}
+
+// AccessRoleSchemaPrivileges mirrors schema_privileges in the
+// access_role_bundles local of main.tf
+var AccessRoleSchemaPrivileges = map[string][]string{
+	"RO": {"USAGE"},
+	"RW": {},
+	"OWNER": {
+		"CREATE TABLE", "CREATE VIEW", "CREATE MATERIALIZED VIEW", "CREATE STAGE", "CREATE FILE FORMAT",
+		"CREATE SEQUENCE", "CREATE FUNCTION", "CREATE PROCEDURE", "CREATE STREAM", "CREATE TASK",
+		"CREATE PIPE", "CREATE DYNAMIC TABLE", "MODIFY", "MONITOR",
+	},
+}
+
+// AccessRoleObjectPrivileges mirrors object_privileges in the
+// access_role_bundles local of main.tf
+var AccessRoleObjectPrivileges = map[string]map[string][]string{
+	"RO":    {"TABLES": {"SELECT"}, "VIEWS": {"SELECT"}, "MATERIALIZED VIEWS": {"SELECT"}},
+	"RW":    {"TABLES": {"INSERT", "UPDATE", "DELETE", "TRUNCATE"}},
+	"OWNER": {},
+}
+
+// RoleName renders the name template the way main.tf does
+func (a AccessRolesConfig) RoleName(databaseName, schemaName, level string) string {
+	template := a.NameTemplate
+	if template == "" {
+		template = "{database}_{schema}_{level}"
+	}
+	return strings.NewReplacer("{database}", databaseName, "{schema}", schemaName, "{level}", level).Replace(template)
+}
+
+// ParentOf returns the role a generated level is granted to: the next level,
+// or ParentRole for OWNER. Empty when OWNER has no parent.
+func (a AccessRolesConfig) ParentOf(databaseName, schemaName, level string) string {
+	for i, l := range AccessRoleLevels {
+		if l != level {
+			continue
+		}
+		if i+1 < len(AccessRoleLevels) {
+			return a.RoleName(databaseName, schemaName, AccessRoleLevels[i+1])
+		}
+	}
+	return derefString(a.ParentRole)
+}
+
+// EnabledFor reports whether a schema gets generated access roles: the
+// variable must enable them and the schema must not opt out
+func (a AccessRolesConfig) EnabledFor(schema SchemaConfig) bool {
+	if !a.Enabled {
+		return false
+	}
+	return schema.AccessRoles == nil || schema.AccessRoles.Enabled == nil || *schema.AccessRoles.Enabled
+}
+
+// DatabasePrivileges returns the database privileges the module grants to the
+// generated access roles: USAGE to the RO role of every schema that has them
+func (a AccessRolesConfig) DatabasePrivileges(cfg DatabaseConfig) map[string][]string {
+	privileges := map[string][]string{}
+	for _, schema := range cfg.AllSchemas() {
+		if a.EnabledFor(schema) {
+			addPrivilegeRoles(privileges, "USAGE", []string{a.RoleName(cfg.Name, schema.Name, "RO")})
+		}
+	}
+	return privileges
+}
+
+// SchemaPrivileges returns the schema privileges of each level's bundle,
+// keyed by privilege name with the generated roles that receive it
+func (a AccessRolesConfig) SchemaPrivileges(databaseName string, schema SchemaConfig) map[string][]string {
+	privileges := map[string][]string{}
+	if !a.EnabledFor(schema) {
+		return privileges
+	}
+	for _, level := range AccessRoleLevels {
+		role := a.RoleName(databaseName, schema.Name, level)
+		for _, privilege := range AccessRoleSchemaPrivileges[level] {
+			addPrivilegeRoles(privileges, privilege, []string{role})
+		}
+	}
+	return privileges
+}
+
+// FuturePrivileges returns the future grants of each level's bundle, keyed
+// by FuturePrivilege like SchemaConfig.FuturePrivileges
+func (a AccessRolesConfig) FuturePrivileges(databaseName string, schema SchemaConfig) map[string][]string {
+	privileges := map[string][]string{}
+	if !a.EnabledFor(schema) {
+		return privileges
+	}
+	for _, level := range AccessRoleLevels {
+		role := a.RoleName(databaseName, schema.Name, level)
+		for objectType, objectPrivileges := range AccessRoleObjectPrivileges[level] {
+			for _, privilege := range objectPrivileges {
+				addPrivilegeRoles(privileges, FuturePrivilege(privilege, objectType), []string{role})
+			}
+		}
+	}
+	return privileges
+}
+
+// SchemaGrants mirrors the schema-level grants object
+type SchemaGrants struct {
+	UsageRoles                  []string `json:"usage_roles,omitempty"`
+	CreateFileFormatRoles       []string `json:"create_file_format_roles,omitempty"`
+	CreateStageRoles            []string `json:"create_stage_roles,omitempty"`
+	CreateTableRoles            []string `json:"create_table_roles,omitempty"`
+	CreatePipeRoles             []string `json:"create_pipe_roles,omitempty"`
+	CreateViewRoles             []string `json:"create_view_roles,omitempty"`
+	CreateMaterializedViewRoles []string `json:"create_materialized_view_roles,omitempty"`
+	CreateSequenceRoles         []string `json:"create_sequence_roles,omitempty"`
+	CreateFunctionRoles         []string `json:"create_function_roles,omitempty"`
+	CreateProcedureRoles        []string `json:"create_procedure_roles,omitempty"`
+	CreateStreamRoles           []string `json:"create_stream_roles,omitempty"`
+	CreateTaskRoles             []string `json:"create_task_roles,omitempty"`
+	CreateDynamicTableRoles     []string `json:"create_dynamic_table_roles,omitempty"`
+	MonitorRoles                []string `json:"monitor_roles,omitempty"`
+
+	// Privileges is the generic privilege name → roles form
+	Privileges map[string][]string `json:"privileges,omitempty"`
+}
+
+// NewDatabaseConfig returns a database config with the variables.tf defaults
+func NewDatabaseConfig(name string) DatabaseConfig {
+	return DatabaseConfig{
+		Name:                    name,
+		DataRetentionTimeInDays: 1,
+		IsTransient:             false,
+	}
+}
+
+// NewSchemaConfig returns a schema config with the variables.tf defaults
+func NewSchemaConfig(name string) SchemaConfig {
+	return SchemaConfig{
+		Name:        name,
+		IsTransient: false,
+		IsManaged:   false,
+	}
+}
+
+// UnmarshalJSON decodes a database config, applying the variables.tf
+// defaults to omitted attributes
+func (c *DatabaseConfig) UnmarshalJSON(data []byte) error {
+	type plain DatabaseConfig
+	cfg := plain(NewDatabaseConfig(""))
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	*c = DatabaseConfig(cfg)
+	return nil
+}
+
+// AllSchemas returns the schemas list followed by the SchemasByKey entries in
+// key order
+func (c DatabaseConfig) AllSchemas() []SchemaConfig {
+	keys := make([]string, 0, len(c.SchemasByKey))
+	for key := range c.SchemasByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	schemas := append([]SchemaConfig{}, c.Schemas...)
+	for _, key := range keys {
+		schemas = append(schemas, c.SchemasByKey[key])
+	}
+	return schemas
+}
+
+// SchemaRetention returns the retention the schema should end up with: its
+// own value, or the database value it inherits when unset. Transient objects
+// retain at most one day, so an inherited value is capped for them.
+func (c DatabaseConfig) SchemaRetention(s SchemaConfig) int {
+	if s.DataRetentionTimeInDays != nil {
+		return *s.DataRetentionTimeInDays
+	}
+	if (s.IsTransient || c.IsTransient) && c.DataRetentionTimeInDays > 1 {
+		return 1
+	}
+	return c.DataRetentionTimeInDays
+}
+
+// Privileges returns the database privileges the module grants, keyed by
+// privilege name with the roles that receive it
+func (c DatabaseConfig) Privileges() map[string][]string {
+	privileges := map[string][]string{}
+	if c.Grants == nil {
+		return privileges
+	}
+	addPrivilegeRoles(privileges, "USAGE", c.Grants.UsageRoles)
+	addPrivilegeRoles(privileges, "MONITOR", c.Grants.MonitorRoles)
+	addPrivilegeRoles(privileges, "CREATE SCHEMA", c.Grants.CreateSchemaRoles)
+	addPrivilegeRoles(privileges, "MODIFY", c.Grants.ModifyRoles)
+	addPrivilegeRoles(privileges, "CREATE DATABASE ROLE", c.Grants.CreateDatabaseRoleRoles)
+	for privilege, roles := range c.Grants.Privileges {
+		addPrivilegeRoles(privileges, strings.ToUpper(privilege), roles)
+	}
+	return privileges
+}
+
+// DatabaseRolePrivileges returns the database privileges granted to the
+// database roles, keyed by privilege name with the database role names
+func (c DatabaseConfig) DatabaseRolePrivileges() map[string][]string {
+	privileges := map[string][]string{}
+	for _, name := range c.databaseRoleNames() {
+		for _, privilege := range c.DatabaseRoles[name].DatabasePrivileges {
+			addPrivilegeRoles(privileges, strings.ToUpper(privilege), []string{name})
+		}
+	}
+	return privileges
+}
+
+// SchemaDatabaseRolePrivileges returns the privileges the database roles are
+// granted on the named schema, keyed like DatabaseRolePrivileges
+func (c DatabaseConfig) SchemaDatabaseRolePrivileges(schemaName string) map[string][]string {
+	privileges := map[string][]string{}
+	for _, name := range c.databaseRoleNames() {
+		for _, privilege := range c.DatabaseRoles[name].SchemaPrivileges[schemaName] {
+			addPrivilegeRoles(privileges, strings.ToUpper(privilege), []string{name})
+		}
+	}
+	return privileges
+}
+
+func (c DatabaseConfig) databaseRoleNames() []string {
+	names := make([]string, 0, len(c.DatabaseRoles))
+	for name := range c.DatabaseRoles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Privileges returns the schema privileges the module grants, keyed by
+// privilege name with the roles that receive it
+func (c SchemaConfig) Privileges() map[string][]string {
+	privileges := map[string][]string{}
+	if c.Grants == nil {
+		return privileges
+	}
+	addPrivilegeRoles(privileges, "USAGE", c.Grants.UsageRoles)
+	addPrivilegeRoles(privileges, "CREATE FILE FORMAT", c.Grants.CreateFileFormatRoles)
+	addPrivilegeRoles(privileges, "CREATE STAGE", c.Grants.CreateStageRoles)
+	addPrivilegeRoles(privileges, "CREATE TABLE", c.Grants.CreateTableRoles)
+	addPrivilegeRoles(privileges, "CREATE PIPE", c.Grants.CreatePipeRoles)
+	addPrivilegeRoles(privileges, "CREATE VIEW", c.Grants.CreateViewRoles)
+	addPrivilegeRoles(privileges, "CREATE MATERIALIZED VIEW", c.Grants.CreateMaterializedViewRoles)
+	addPrivilegeRoles(privileges, "CREATE SEQUENCE", c.Grants.CreateSequenceRoles)
+	addPrivilegeRoles(privileges, "CREATE FUNCTION", c.Grants.CreateFunctionRoles)
+	addPrivilegeRoles(privileges, "CREATE PROCEDURE", c.Grants.CreateProcedureRoles)
+	addPrivilegeRoles(privileges, "CREATE STREAM", c.Grants.CreateStreamRoles)
+	addPrivilegeRoles(privileges, "CREATE TASK", c.Grants.CreateTaskRoles)
+	addPrivilegeRoles(privileges, "CREATE DYNAMIC TABLE", c.Grants.CreateDynamicTableRoles)
+	addPrivilegeRoles(privileges, "MONITOR", c.Grants.MonitorRoles)
+	for privilege, roles := range c.Grants.Privileges {
+		addPrivilegeRoles(privileges, strings.ToUpper(privilege), roles)
+	}
+	return privileges
+}
+
+// FuturePrivileges returns the schema future grants keyed by
+// FuturePrivilege (e.g. "SELECT ON FUTURE TABLES") with the roles that
+// receive it
+func (c SchemaConfig) FuturePrivileges() map[string][]string {
+	privileges := map[string][]string{}
+	for objectType, grants := range c.FutureGrants {
+		for privilege, roles := range grants {
+			addPrivilegeRoles(privileges, FuturePrivilege(privilege, objectType), roles)
+		}
+	}
+	return privileges
+}
+
+// FuturePrivilege names a privilege on future objects of a plural object type
+func FuturePrivilege(privilege, objectTypePlural string) string {
+	return strings.ToUpper(privilege) + " ON FUTURE " + strings.ToUpper(objectTypePlural)
+}
+
+func addPrivilegeRoles(privileges map[string][]string, privilege string, roles []string) {
+	if len(roles) > 0 {
+		privileges[privilege] = append(privileges[privilege], roles...)
+	}
+}
+
+// MergePrivileges combines privilege → roles maps into a new map
+func MergePrivileges(maps ...map[string][]string) map[string][]string {
+	merged := map[string][]string{}
+	for _, m := range maps {
+		for privilege, roles := range m {
+			addPrivilegeRoles(merged, privilege, roles)
+		}
+	}
+	return merged
+}
+
+// TerraformValue converts the configs into the generic value terratest
+// passes to -var. Omitted optional attributes fall back to their defaults.
+func (c DatabaseConfigs) TerraformValue() (map[string]interface{}, error) {
+	return terraformValue(c)
+}
+
+// TerraformValue converts the access roles settings into the value passed to
+// the access_roles variable
+func (a AccessRolesConfig) TerraformValue() (map[string]interface{}, error) {
+	return terraformValue(a)
+}
+
+func terraformValue(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var value map[string]interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
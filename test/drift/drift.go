@@ -0,0 +1,358 @@
+// File: test/drift/drift.go
+
+// Package drift compares database_configs against a live Snowflake account
+// and reports every database, schema, property and grant that differs.
+package drift
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/show"
+)
+
+// ignoredSchemas are created by Snowflake in every database and are only
+// reported as extra when a config declares them
+var ignoredSchemas = []string{"INFORMATION_SCHEMA", "PUBLIC"}
+
+// Options tunes what Detect treats as unmanaged
+type Options struct {
+	// DatabaseLike is a SHOW DATABASES LIKE pattern. Databases matching it that
+	// are not in the configs are reported as extra. Empty disables the check.
+	DatabaseLike string
+	// AccessRoles is the access_roles variable. When enabled, the grants of
+	// the generated RO, RW and OWNER roles are expected too.
+	AccessRoles dbconfig.AccessRolesConfig
+}
+
+// Report lists every difference between database_configs and Snowflake.
+//
+// Grants on databases and schemas are compared for account roles and
+// database roles; future grants only for account roles, as the module grants
+// none to database roles. OWNERSHIP grants are left out, as ownership is
+// compared as the owner_role property when a config sets it. Grants to
+// shares, applications and other grantee types are not managed by the module
+// and are ignored.
+type Report struct {
+	MissingDatabases   []string
+	ExtraDatabases     []string
+	MissingSchemas     []string
+	ExtraSchemas       []string
+	PropertyMismatches []PropertyMismatch
+	MissingGrants      []GrantDrift
+	ExtraGrants        []GrantDrift
+}
+
+// PropertyMismatch is a database or schema property whose live value differs
+// from the configured one. Object is DB or DB.SCHEMA.
+type PropertyMismatch struct {
+	Object   string
+	Property string
+	Expected interface{}
+	Actual   interface{}
+}
+
+// GrantDrift is a privilege on a database or schema that is missing from, or
+// not declared in, the configs. Role is a database role name, without the
+// database qualifier, when DatabaseRole is set.
+type GrantDrift struct {
+	Object       string
+	Privilege    string
+	Role         string
+	DatabaseRole bool
+}
+
+// HasDrift reports whether the report contains any difference
+func (r Report) HasDrift() bool {
+	return len(r.MissingDatabases)+len(r.ExtraDatabases)+len(r.MissingSchemas)+len(r.ExtraSchemas)+
+		len(r.PropertyMismatches)+len(r.MissingGrants)+len(r.ExtraGrants) > 0
+}
+
+// String renders the report one difference per line
+func (r Report) String() string {
+	if !r.HasDrift() {
+		return "no drift"
+	}
+
+	var b strings.Builder
+	for _, name := range r.MissingDatabases {
+		fmt.Fprintf(&b, "missing database %s\n", name)
+	}
+	for _, name := range r.ExtraDatabases {
+		fmt.Fprintf(&b, "extra database %s\n", name)
+	}
+	for _, name := range r.MissingSchemas {
+		fmt.Fprintf(&b, "missing schema %s\n", name)
+	}
+	for _, name := range r.ExtraSchemas {
+		fmt.Fprintf(&b, "extra schema %s\n", name)
+	}
+	for _, m := range r.PropertyMismatches {
+		fmt.Fprintf(&b, "%s: %s is %v, expected %v\n", m.Object, m.Property, m.Actual, m.Expected)
+	}
+	for _, g := range r.MissingGrants {
+		fmt.Fprintf(&b, "%s: missing %s to %s\n", g.Object, g.Privilege, g.grantee())
+	}
+	for _, g := range r.ExtraGrants {
+		fmt.Fprintf(&b, "%s: extra %s to %s\n", g.Object, g.Privilege, g.grantee())
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Detect compares the configs against the live account and returns every
+// difference it finds. Only errors talking to Snowflake are returned as err.
+func Detect(db *sql.DB, configs dbconfig.DatabaseConfigs, opts Options) (Report, error) {
+	var report Report
+
+	keys := make([]string, 0, len(configs))
+	for k := range configs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		cfg := configs[key]
+
+		var found []show.DatabaseProps
+		q := fmt.Sprintf("SHOW DATABASES LIKE '%s';", escapeLike(cfg.Name))
+		if err := show.Query(db, q, &found); err != nil {
+			return report, err
+		}
+		props, ok := findByName(found, cfg.Name, func(p show.DatabaseProps) string { return p.Name })
+		if !ok {
+			report.MissingDatabases = append(report.MissingDatabases, cfg.Name)
+			continue
+		}
+
+		report.compare(cfg.Name, "comment", derefString(cfg.Comment), props.Comment)
+		report.compare(cfg.Name, "data_retention_time_in_days", cfg.DataRetentionTimeInDays, props.DataRetentionTimeInDays)
+		report.compare(cfg.Name, "is_transient", cfg.IsTransient, props.IsTransient)
+		if cfg.OwnerRole != nil {
+			report.compare(cfg.Name, "owner_role", *cfg.OwnerRole, props.Owner)
+		}
+
+		q = fmt.Sprintf("SHOW GRANTS ON DATABASE %s;", props.Name)
+		expected := grantsTo(props.Name, dbconfig.MergePrivileges(cfg.Privileges(), opts.AccessRoles.DatabasePrivileges(cfg)), false)
+		expected = append(expected, grantsTo(props.Name, cfg.DatabaseRolePrivileges(), true)...)
+		if err := report.compareGrants(db, q, props.Name, expected); err != nil {
+			return report, err
+		}
+
+		if err := report.compareSchemas(db, cfg, props.Name, opts.AccessRoles); err != nil {
+			return report, err
+		}
+	}
+
+	if opts.DatabaseLike != "" {
+		var all []show.DatabaseProps
+		q := fmt.Sprintf("SHOW DATABASES LIKE '%s';", escapeLike(opts.DatabaseLike))
+		if err := show.Query(db, q, &all); err != nil {
+			return report, err
+		}
+		for _, props := range all {
+			declared := false
+			for _, cfg := range configs {
+				declared = declared || strings.EqualFold(cfg.Name, props.Name)
+			}
+			if !declared {
+				report.ExtraDatabases = append(report.ExtraDatabases, props.Name)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (r *Report) compareSchemas(db *sql.DB, cfg dbconfig.DatabaseConfig, databaseName string, access dbconfig.AccessRolesConfig) error {
+	var live []show.SchemaProps
+	if err := show.Query(db, fmt.Sprintf("SHOW SCHEMAS IN DATABASE %s;", databaseName), &live); err != nil {
+		return err
+	}
+
+	for _, schema := range cfg.AllSchemas() {
+		object := databaseName + "." + schema.Name
+		props, ok := findByName(live, schema.Name, func(p show.SchemaProps) string { return p.Name })
+		if !ok {
+			r.MissingSchemas = append(r.MissingSchemas, object)
+			continue
+		}
+
+		r.compare(object, "comment", derefString(schema.Comment), props.Comment)
+		r.compare(object, "data_retention_time_in_days", cfg.SchemaRetention(schema), props.DataRetentionTimeInDays)
+		// Schemas in a transient database are always transient
+		r.compare(object, "is_transient", schema.IsTransient || cfg.IsTransient, props.IsTransient)
+		r.compare(object, "is_managed", schema.IsManaged, props.IsManagedAccess)
+		if schema.OwnerRole != nil {
+			r.compare(object, "owner_role", *schema.OwnerRole, props.Owner)
+		}
+
+		q := fmt.Sprintf("SHOW GRANTS ON SCHEMA %s.%s;", databaseName, props.Name)
+		expected := grantsTo(object, dbconfig.MergePrivileges(schema.Privileges(), access.SchemaPrivileges(cfg.Name, schema)), false)
+		expected = append(expected, grantsTo(object, cfg.SchemaDatabaseRolePrivileges(schema.Name), true)...)
+		if err := r.compareGrants(db, q, object, expected); err != nil {
+			return err
+		}
+		expectedFuture := dbconfig.MergePrivileges(schema.FuturePrivileges(), access.FuturePrivileges(cfg.Name, schema))
+		if err := r.compareFutureGrants(db, databaseName, props.Name, object, grantsTo(object, expectedFuture, false)); err != nil {
+			return err
+		}
+	}
+
+	for _, props := range live {
+		if _, ok := findByName(cfg.AllSchemas(), props.Name, func(s dbconfig.SchemaConfig) string { return s.Name }); ok {
+			continue
+		}
+		if containsFold(ignoredSchemas, props.Name) {
+			continue
+		}
+		r.ExtraSchemas = append(r.ExtraSchemas, databaseName+"."+props.Name)
+	}
+
+	return nil
+}
+
+// compareGrants diffs the grants from a SHOW GRANTS ON statement to account
+// and database roles against the expected ones. OWNERSHIP and other grantee
+// types are skipped, see Report.
+func (r *Report) compareGrants(db *sql.DB, q, object string, expected []GrantDrift) error {
+	var live []show.GrantInfo
+	if err := show.Query(db, q, &live); err != nil {
+		return err
+	}
+
+	var actual []GrantDrift
+	for _, g := range live {
+		if strings.EqualFold(g.Privilege, "OWNERSHIP") {
+			continue
+		}
+		switch {
+		case strings.EqualFold(g.GrantedTo, "ROLE"):
+			actual = append(actual, GrantDrift{Object: object, Privilege: g.Privilege, Role: g.Grantee})
+		case g.IsDatabaseRole():
+			actual = append(actual, GrantDrift{Object: object, Privilege: g.Privilege, Role: g.GranteeRoleName(), DatabaseRole: true})
+		}
+	}
+
+	r.diffGrants(expected, actual)
+	return nil
+}
+
+// compareFutureGrants diffs SHOW FUTURE GRANTS IN SCHEMA against the expected
+// future privileges, named as by SchemaConfig.FuturePrivileges
+func (r *Report) compareFutureGrants(db *sql.DB, databaseName, schemaName, object string, expected []GrantDrift) error {
+	var live []show.FutureGrantInfo
+	q := fmt.Sprintf("SHOW FUTURE GRANTS IN SCHEMA %s.%s;", databaseName, schemaName)
+	if err := show.Query(db, q, &live); err != nil {
+		return err
+	}
+
+	var actual []GrantDrift
+	for _, g := range live {
+		if !strings.EqualFold(g.GrantTo, "ROLE") {
+			continue
+		}
+		actual = append(actual, GrantDrift{Object: object, Privilege: dbconfig.FuturePrivilege(g.Privilege, g.ObjectTypePlural()), Role: g.Grantee})
+	}
+
+	r.diffGrants(expected, actual)
+	return nil
+}
+
+// diffGrants records expected grants missing from actual as MissingGrants and
+// actual grants that are not expected as ExtraGrants
+func (r *Report) diffGrants(expected, actual []GrantDrift) {
+	have := map[string]bool{}
+	for _, g := range actual {
+		have[g.key()] = true
+	}
+
+	want := map[string]bool{}
+	for _, g := range expected {
+		want[g.key()] = true
+		if !have[g.key()] {
+			r.MissingGrants = append(r.MissingGrants, g)
+		}
+	}
+
+	for _, g := range actual {
+		if !want[g.key()] {
+			r.ExtraGrants = append(r.ExtraGrants, g)
+			want[g.key()] = true
+		}
+	}
+}
+
+// grantsTo lists a privilege → roles map as the grants expected on object
+func grantsTo(object string, privileges map[string][]string, databaseRole bool) []GrantDrift {
+	var grants []GrantDrift
+	for _, privilege := range sortedKeys(privileges) {
+		for _, role := range privileges[privilege] {
+			grants = append(grants, GrantDrift{Object: object, Privilege: privilege, Role: role, DatabaseRole: databaseRole})
+		}
+	}
+	return grants
+}
+
+func (r *Report) compare(object, property string, expected, actual interface{}) {
+	if expected != actual {
+		r.PropertyMismatches = append(r.PropertyMismatches, PropertyMismatch{
+			Object:   object,
+			Property: property,
+			Expected: expected,
+			Actual:   actual,
+		})
+	}
+}
+
+func (g GrantDrift) key() string {
+	return fmt.Sprintf("%s\x00%s\x00%t", strings.ToUpper(g.Privilege), strings.ToUpper(g.Role), g.DatabaseRole)
+}
+
+func (g GrantDrift) grantee() string {
+	if g.DatabaseRole {
+		return "database role " + g.Role
+	}
+	return "role " + g.Role
+}
+
+func findByName[T any](items []T, name string, nameOf func(T) string) (T, bool) {
+	for _, item := range items {
+		if strings.EqualFold(nameOf(item), name) {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func escapeLike(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,217 @@
+// File: test/drift_test.go
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/drift"
+)
+
+// TestDetectDriftInSync verifies a catalog matching the configs reports no drift
+func TestDetectDriftInSync(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	warehouse := catalog.addDatabase(fakeDatabase{Name: "TT_DW", Comment: "Warehouse", RetentionTime: 7})
+	catalog.grantOnDatabase(warehouse, "USAGE", "TT_READER")
+	curated := catalog.addSchema(warehouse, fakeSchema{Name: "CURATED", RetentionTime: 7, IsManagedAccess: true})
+	catalog.grantOnSchema(curated, "USAGE", "TT_READER")
+	catalog.addSchema(warehouse, fakeSchema{Name: "PUBLIC", RetentionTime: 7})
+
+	curatedCfg := dbconfig.NewSchemaConfig("CURATED")
+	curatedCfg.IsManaged = true
+	curatedCfg.Grants = &dbconfig.SchemaGrants{UsageRoles: []string{"TT_READER"}}
+
+	warehouseCfg := dbconfig.NewDatabaseConfig("TT_DW")
+	warehouseCfg.Comment = ptr("Warehouse")
+	warehouseCfg.DataRetentionTimeInDays = 7
+	warehouseCfg.Grants = &dbconfig.DatabaseGrants{UsageRoles: []string{"TT_READER"}}
+	warehouseCfg.Schemas = []dbconfig.SchemaConfig{curatedCfg}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	report, err := drift.Detect(db, dbconfig.DatabaseConfigs{"warehouse": warehouseCfg}, drift.Options{DatabaseLike: "TT_%"})
+	require.NoError(t, err)
+	require.False(t, report.HasDrift(), report.String())
+}
+
+// TestDetectDriftReportsEveryDifference verifies each kind of drift is reported
+func TestDetectDriftReportsEveryDifference(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	warehouse := catalog.addDatabase(fakeDatabase{Name: "TT_DW", Comment: "Changed by hand", RetentionTime: 1})
+	catalog.grantOnDatabase(warehouse, "USAGE", "TT_ROGUE")
+	raw := catalog.addSchema(warehouse, fakeSchema{Name: "RAW", RetentionTime: 1, IsTransient: true})
+	catalog.grantOnSchema(raw, "CREATE TABLE", "TT_WRITER")
+	catalog.grantFutureInSchema(raw, "VIEW", "SELECT", "TT_ROGUE")
+	catalog.addSchema(warehouse, fakeSchema{Name: "SCRATCH", RetentionTime: 1})
+	catalog.addDatabase(fakeDatabase{Name: "TT_UNMANAGED", RetentionTime: 1})
+
+	rawCfg := dbconfig.NewSchemaConfig("RAW")
+	rawCfg.IsManaged = true
+	rawCfg.Grants = &dbconfig.SchemaGrants{
+		UsageRoles:       []string{"TT_READER"},
+		CreateTableRoles: []string{"TT_WRITER"},
+	}
+	rawCfg.FutureGrants = map[string]map[string][]string{"TABLES": {"SELECT": {"TT_READER"}}}
+
+	warehouseCfg := dbconfig.NewDatabaseConfig("TT_DW")
+	warehouseCfg.Comment = ptr("Warehouse")
+	warehouseCfg.DataRetentionTimeInDays = 7
+	warehouseCfg.OwnerRole = ptr("TT_DW_ADMIN")
+	warehouseCfg.Grants = &dbconfig.DatabaseGrants{UsageRoles: []string{"TT_READER"}}
+	warehouseCfg.Schemas = []dbconfig.SchemaConfig{rawCfg, dbconfig.NewSchemaConfig("CURATED")}
+
+	configs := dbconfig.DatabaseConfigs{
+		"warehouse": warehouseCfg,
+		"missing":   dbconfig.NewDatabaseConfig("TT_MISSING"),
+	}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	report, err := drift.Detect(db, configs, drift.Options{DatabaseLike: "TT_%"})
+	require.NoError(t, err)
+	require.True(t, report.HasDrift())
+
+	require.Equal(t, []string{"TT_MISSING"}, report.MissingDatabases)
+	require.Equal(t, []string{"TT_UNMANAGED"}, report.ExtraDatabases)
+	require.Equal(t, []string{"TT_DW.CURATED"}, report.MissingSchemas)
+	require.Equal(t, []string{"TT_DW.SCRATCH"}, report.ExtraSchemas)
+	require.ElementsMatch(t, []drift.PropertyMismatch{
+		{Object: "TT_DW", Property: "comment", Expected: "Warehouse", Actual: "Changed by hand"},
+		{Object: "TT_DW", Property: "data_retention_time_in_days", Expected: 7, Actual: 1},
+		{Object: "TT_DW", Property: "owner_role", Expected: "TT_DW_ADMIN", Actual: "SYSADMIN"},
+		{Object: "TT_DW.RAW", Property: "data_retention_time_in_days", Expected: 7, Actual: 1},
+		{Object: "TT_DW.RAW", Property: "is_transient", Expected: false, Actual: true},
+		{Object: "TT_DW.RAW", Property: "is_managed", Expected: true, Actual: false},
+	}, report.PropertyMismatches)
+	require.ElementsMatch(t, []drift.GrantDrift{
+		{Object: "TT_DW", Privilege: "USAGE", Role: "TT_READER"},
+		{Object: "TT_DW.RAW", Privilege: "USAGE", Role: "TT_READER"},
+		{Object: "TT_DW.RAW", Privilege: "SELECT ON FUTURE TABLES", Role: "TT_READER"},
+	}, report.MissingGrants)
+	require.Equal(t, []drift.GrantDrift{
+		{Object: "TT_DW", Privilege: "USAGE", Role: "TT_ROGUE"},
+		{Object: "TT_DW.RAW", Privilege: "SELECT ON FUTURE VIEWS", Role: "TT_ROGUE"},
+	}, report.ExtraGrants)
+	require.Contains(t, report.String(), "TT_DW: extra USAGE to role TT_ROGUE")
+}
+
+// TestDetectDriftWithAccessRoles verifies the grants of the generated RO, RW
+// and OWNER roles are expected when access roles are enabled, and are only
+// reported as extra when they are not
+func TestDetectDriftWithAccessRoles(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	access := dbconfig.AccessRolesConfig{Enabled: true, NameTemplate: "{database}_{schema}_{level}"}
+
+	warehouse := catalog.addDatabase(fakeDatabase{Name: "TT_DW", RetentionTime: 1})
+	catalog.grantOnDatabase(warehouse, "USAGE", "TT_READER")
+	curated := catalog.addSchema(warehouse, fakeSchema{Name: "CURATED", RetentionTime: 1})
+	catalog.grantOnSchema(curated, "USAGE", "TT_READER")
+	catalog.addSchema(warehouse, fakeSchema{Name: "SCRATCH", RetentionTime: 1})
+
+	catalog.grantOnDatabase(warehouse, "USAGE", "TT_DW_CURATED_RO")
+	for _, level := range dbconfig.AccessRoleLevels {
+		role := access.RoleName("TT_DW", "CURATED", level)
+		for _, privilege := range dbconfig.AccessRoleSchemaPrivileges[level] {
+			catalog.grantOnSchema(curated, privilege, role)
+		}
+		for objectType, privileges := range dbconfig.AccessRoleObjectPrivileges[level] {
+			for _, privilege := range privileges {
+				catalog.grantFutureInSchema(curated, strings.TrimSuffix(objectType, "S"), privilege, role)
+			}
+		}
+	}
+
+	curatedCfg := dbconfig.NewSchemaConfig("CURATED")
+	curatedCfg.Grants = &dbconfig.SchemaGrants{UsageRoles: []string{"TT_READER"}}
+	scratchCfg := dbconfig.NewSchemaConfig("SCRATCH")
+	scratchCfg.AccessRoles = &dbconfig.SchemaAccessRoles{Enabled: ptr(false)}
+
+	warehouseCfg := dbconfig.NewDatabaseConfig("TT_DW")
+	warehouseCfg.Grants = &dbconfig.DatabaseGrants{UsageRoles: []string{"TT_READER"}}
+	warehouseCfg.Schemas = []dbconfig.SchemaConfig{curatedCfg, scratchCfg}
+	configs := dbconfig.DatabaseConfigs{"warehouse": warehouseCfg}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	report, err := drift.Detect(db, configs, drift.Options{AccessRoles: access})
+	require.NoError(t, err)
+	require.False(t, report.HasDrift(), report.String())
+
+	report, err = drift.Detect(db, configs, drift.Options{})
+	require.NoError(t, err)
+	require.Empty(t, report.MissingGrants)
+	require.Contains(t, report.ExtraGrants, drift.GrantDrift{Object: "TT_DW", Privilege: "USAGE", Role: "TT_DW_CURATED_RO"})
+	require.Contains(t, report.ExtraGrants, drift.GrantDrift{Object: "TT_DW.CURATED", Privilege: "CREATE TABLE", Role: "TT_DW_CURATED_OWNER"})
+	require.Contains(t, report.ExtraGrants, drift.GrantDrift{Object: "TT_DW.CURATED", Privilege: "INSERT ON FUTURE TABLES", Role: "TT_DW_CURATED_RW"})
+}
+
+// TestDetectDriftWithDatabaseRoles verifies grants to database roles are
+// compared like grants to account roles, and that an account role with the
+// same name as a database role does not stand in for it
+func TestDetectDriftWithDatabaseRoles(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	sales := catalog.addDatabase(fakeDatabase{Name: "TT_SALES", RetentionTime: 1})
+	catalog.grantOnDatabaseToDatabaseRole(sales, "USAGE", "SALES_READ")
+	raw := catalog.addSchema(sales, fakeSchema{Name: "RAW", RetentionTime: 1})
+	catalog.grantOnSchemaToDatabaseRole(sales, raw, "USAGE", "SALES_READ")
+	catalog.grantOnSchemaToDatabaseRole(sales, raw, "CREATE TABLE", "SALES_ROGUE")
+	catalog.grantOnSchema(raw, "MONITOR", "SALES_READ")
+
+	salesCfg := dbconfig.NewDatabaseConfig("TT_SALES")
+	salesCfg.Schemas = []dbconfig.SchemaConfig{dbconfig.NewSchemaConfig("RAW")}
+	salesCfg.DatabaseRoles = map[string]dbconfig.DatabaseRoleConfig{
+		"SALES_READ": {
+			DatabasePrivileges: []string{"usage"},
+			SchemaPrivileges:   map[string][]string{"RAW": {"USAGE", "MONITOR"}},
+		},
+	}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	report, err := drift.Detect(db, dbconfig.DatabaseConfigs{"sales": salesCfg}, drift.Options{})
+	require.NoError(t, err)
+	require.Equal(t, []drift.GrantDrift{
+		{Object: "TT_SALES.RAW", Privilege: "MONITOR", Role: "SALES_READ", DatabaseRole: true},
+	}, report.MissingGrants)
+	require.ElementsMatch(t, []drift.GrantDrift{
+		{Object: "TT_SALES.RAW", Privilege: "CREATE TABLE", Role: "SALES_ROGUE", DatabaseRole: true},
+		{Object: "TT_SALES.RAW", Privilege: "MONITOR", Role: "SALES_READ"},
+	}, report.ExtraGrants)
+	require.Contains(t, report.String(), "TT_SALES.RAW: missing MONITOR to database role SALES_READ")
+}
+
+// TestDriftAudit compares a real account against a database_configs JSON file.
+// It is meant for scheduled audits and is skipped unless DRIFT_AUDIT_CONFIG
+// points at a file holding the database_configs value.
+func TestDriftAudit(t *testing.T) {
+	path := os.Getenv("DRIFT_AUDIT_CONFIG")
+	if path == "" {
+		t.Skip("DRIFT_AUDIT_CONFIG not set")
+	}
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var configs dbconfig.DatabaseConfigs
+	require.NoError(t, json.Unmarshal(raw, &configs), "Failed to parse %s", path)
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	opts := drift.Options{DatabaseLike: os.Getenv("DRIFT_AUDIT_DATABASE_LIKE")}
+	if value := os.Getenv("DRIFT_AUDIT_ACCESS_ROLES"); value != "" {
+		require.NoError(t, json.Unmarshal([]byte(value), &opts.AccessRoles), "Failed to parse DRIFT_AUDIT_ACCESS_ROLES")
+	}
+
+	report, err := drift.Detect(db, configs, opts)
+	require.NoError(t, err)
+	require.False(t, report.HasDrift(), "Drift detected:\n%s", report)
+}
@@ -0,0 +1,1000 @@
+// File: test/fake_snowflake_helpers_test.go
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriverName is the database/sql driver name of the in-memory Snowflake
+// fake. openSnowflake selects it when SNOWFLAKE_TEST_DRIVER is set to "fake".
+const fakeDriverName = "snowflake-fake"
+
+// fakeDefaultCatalog is the catalog used when no DSN is given.
+const fakeDefaultCatalog = "default"
+
+func init() {
+	sql.Register(fakeDriverName, &fakeDriver{})
+}
+
+// useFakeSnowflake switches openSnowflake to a fresh in-memory catalog named
+// after the test. Tests using it must not call t.Parallel.
+func useFakeSnowflake(t *testing.T) *fakeCatalog {
+	t.Helper()
+
+	t.Setenv("SNOWFLAKE_TEST_DRIVER", "fake")
+	t.Setenv("SNOWFLAKE_FAKE_CATALOG", t.Name())
+	resetFakeCatalog(t.Name())
+
+	return getFakeCatalog(t.Name())
+}
+
+// fakeCatalog is an in-memory set of databases, schemas and grants that
+// answers the SHOW statements used by the test helpers.
+type fakeCatalog struct {
+	mu         sync.Mutex
+	databases  []*fakeDatabase
+	roleGrants []fakeRoleGrant
+}
+
+// fakeRoleGrant records account role Role being granted to account role Parent.
+type fakeRoleGrant struct {
+	Role   string
+	Parent string
+}
+
+// fakeDatabase is a database in the catalog. An empty Kind is reported as
+// STANDARD, and an empty Budget or ResourceGroup as NULL. A database with a
+// DroppedOn time only shows up in SHOW DATABASES HISTORY.
+type fakeDatabase struct {
+	Name          string
+	Comment       string
+	Owner         string
+	Origin        string
+	Kind          string
+	Budget        string
+	ResourceGroup string
+	RetentionTime int
+	IsTransient   bool
+	CreatedOn     time.Time
+	DroppedOn     time.Time
+	Parameters    map[string]string
+	Tags          map[string]string
+	Schemas       []*fakeSchema
+	Grants        []fakeGrant
+	DatabaseRoles []*fakeDatabaseRole
+}
+
+// fakeDatabaseRole is a database role and the account roles it is granted to.
+type fakeDatabaseRole struct {
+	Name      string
+	Comment   string
+	Owner     string
+	CreatedOn time.Time
+	GrantedTo []string
+}
+
+// fakeSchema is a schema in a database. An empty Budget or ResourceGroup is
+// reported as NULL. A schema with a DroppedOn time only shows up in SHOW
+// SCHEMAS HISTORY.
+type fakeSchema struct {
+	Name            string
+	Comment         string
+	Owner           string
+	Budget          string
+	ResourceGroup   string
+	RetentionTime   int
+	IsTransient     bool
+	IsManagedAccess bool
+	CreatedOn       time.Time
+	DroppedOn       time.Time
+	Parameters      map[string]string
+	Tags            map[string]string
+	Grants          []fakeGrant
+	FutureGrants    []fakeFutureGrant
+	Objects         []*fakeObject
+}
+
+// fakeObject is a table or view in a schema. Kind is singular, as SHOW
+// OBJECTS reports it (e.g. TABLE, VIEW).
+type fakeObject struct {
+	Name      string
+	Kind      string
+	Owner     string
+	CreatedOn time.Time
+	Grants    []fakeGrant
+}
+
+type fakeGrant struct {
+	Privilege string
+	GrantedTo string
+	Grantee   string
+}
+
+// fakeFutureGrant is a future grant in a schema. ObjectType is singular, as
+// Snowflake reports it in the grant_on column (e.g. TABLE).
+type fakeFutureGrant struct {
+	Privilege  string
+	ObjectType string
+	Grantee    string
+}
+
+var (
+	fakeCatalogsMu sync.Mutex
+	fakeCatalogs   = map[string]*fakeCatalog{}
+)
+
+// getFakeCatalog returns the catalog registered under name, creating an empty
+// one on first use. Tests that run in parallel should use distinct names.
+func getFakeCatalog(name string) *fakeCatalog {
+	fakeCatalogsMu.Lock()
+	defer fakeCatalogsMu.Unlock()
+
+	if name == "" {
+		name = fakeDefaultCatalog
+	}
+	c, ok := fakeCatalogs[name]
+	if !ok {
+		c = &fakeCatalog{}
+		fakeCatalogs[name] = c
+	}
+	return c
+}
+
+// resetFakeCatalog drops every object from the catalog registered under name.
+func resetFakeCatalog(name string) {
+	c := getFakeCatalog(name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.databases = nil
+	c.roleGrants = nil
+}
+
+// addDatabase adds a database to the catalog and returns it for further setup.
+// A zero CreatedOn or empty Owner is filled in with a fixed default.
+func (c *fakeCatalog) addDatabase(d fakeDatabase) *fakeDatabase {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d.CreatedOn.IsZero() {
+		d.CreatedOn = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if d.Owner == "" {
+		d.Owner = "SYSADMIN"
+	}
+	c.databases = append(c.databases, &d)
+	return &d
+}
+
+// addSchema adds a schema to the database and returns it for further setup.
+func (c *fakeCatalog) addSchema(d *fakeDatabase, s fakeSchema) *fakeSchema {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s.CreatedOn.IsZero() {
+		s.CreatedOn = d.CreatedOn
+	}
+	if s.Owner == "" {
+		s.Owner = d.Owner
+	}
+	d.Schemas = append(d.Schemas, &s)
+	return &s
+}
+
+// grantOnDatabase records a privilege on the database granted to a role.
+func (c *fakeCatalog) grantOnDatabase(d *fakeDatabase, privilege, role string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d.Grants = append(d.Grants, fakeGrant{Privilege: privilege, GrantedTo: "ROLE", Grantee: role})
+}
+
+// grantOnSchema records a privilege on the schema granted to a role.
+func (c *fakeCatalog) grantOnSchema(s *fakeSchema, privilege, role string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s.Grants = append(s.Grants, fakeGrant{Privilege: privilege, GrantedTo: "ROLE", Grantee: role})
+}
+
+// addDatabaseRole adds a database role to the database and returns it.
+func (c *fakeCatalog) addDatabaseRole(d *fakeDatabase, r fakeDatabaseRole) *fakeDatabaseRole {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if r.CreatedOn.IsZero() {
+		r.CreatedOn = d.CreatedOn
+	}
+	if r.Owner == "" {
+		r.Owner = d.Owner
+	}
+	d.DatabaseRoles = append(d.DatabaseRoles, &r)
+	return &r
+}
+
+// grantDatabaseRole records the database role being granted to an account role.
+func (c *fakeCatalog) grantDatabaseRole(r *fakeDatabaseRole, accountRole string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r.GrantedTo = append(r.GrantedTo, accountRole)
+}
+
+// grantOnDatabaseToDatabaseRole records a privilege on the database granted
+// to one of its database roles. Snowflake reports the grantee qualified.
+func (c *fakeCatalog) grantOnDatabaseToDatabaseRole(d *fakeDatabase, privilege, databaseRole string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d.Grants = append(d.Grants, fakeGrant{Privilege: privilege, GrantedTo: "DATABASE_ROLE", Grantee: d.Name + "." + databaseRole})
+}
+
+// grantOnSchemaToDatabaseRole records a privilege on the schema granted to a
+// database role of database d.
+func (c *fakeCatalog) grantOnSchemaToDatabaseRole(d *fakeDatabase, s *fakeSchema, privilege, databaseRole string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s.Grants = append(s.Grants, fakeGrant{Privilege: privilege, GrantedTo: "DATABASE_ROLE", Grantee: d.Name + "." + databaseRole})
+}
+
+// grantRole records an account role being granted to another account role.
+func (c *fakeCatalog) grantRole(role, parent string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.roleGrants = append(c.roleGrants, fakeRoleGrant{Role: role, Parent: parent})
+}
+
+// grantFutureInSchema records a privilege on future objects of a singular
+// object type in the schema granted to a role.
+func (c *fakeCatalog) grantFutureInSchema(s *fakeSchema, objectType, privilege, role string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s.FutureGrants = append(s.FutureGrants, fakeFutureGrant{Privilege: privilege, ObjectType: objectType, Grantee: role})
+}
+
+// addObject adds a table or view to the schema and returns it for further setup.
+func (c *fakeCatalog) addObject(s *fakeSchema, o fakeObject) *fakeObject {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if o.CreatedOn.IsZero() {
+		o.CreatedOn = s.CreatedOn
+	}
+	if o.Owner == "" {
+		o.Owner = s.Owner
+	}
+	s.Objects = append(s.Objects, &o)
+	return &o
+}
+
+// grantOnObject records a privilege on a table or view granted to a role.
+func (c *fakeCatalog) grantOnObject(o *fakeObject, privilege, role string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	o.Grants = append(o.Grants, fakeGrant{Privilege: privilege, GrantedTo: "ROLE", Grantee: role})
+}
+
+func (c *fakeCatalog) findDatabase(name string) *fakeDatabase {
+	for _, d := range c.databases {
+		if d.DroppedOn.IsZero() && identifierEqual(d.Name, name) {
+			return d
+		}
+	}
+	return nil
+}
+
+func (s *fakeSchema) findObject(kind, name string) *fakeObject {
+	for _, o := range s.Objects {
+		if strings.EqualFold(o.Kind, kind) && identifierEqual(o.Name, name) {
+			return o
+		}
+	}
+	return nil
+}
+
+func (d *fakeDatabase) findDatabaseRole(name string) *fakeDatabaseRole {
+	for _, r := range d.DatabaseRoles {
+		if identifierEqual(r.Name, name) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (d *fakeDatabase) findSchema(name string) *fakeSchema {
+	for _, s := range d.Schemas {
+		if s.DroppedOn.IsZero() && identifierEqual(s.Name, name) {
+			return s
+		}
+	}
+	return nil
+}
+
+// identifierEqual compares an object name with an identifier from a statement.
+// Quoted identifiers are case-sensitive, unquoted ones are not.
+func identifierEqual(name, ident string) bool {
+	if len(ident) >= 2 && strings.HasPrefix(ident, `"`) && strings.HasSuffix(ident, `"`) {
+		return name == ident[1:len(ident)-1]
+	}
+	return strings.EqualFold(name, ident)
+}
+
+// likeMatch reports whether s matches a case-insensitive SQL LIKE pattern.
+func likeMatch(pattern, s string) bool {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String()).MatchString(s)
+}
+
+var (
+	showDatabasesRe = regexp.MustCompile(`(?is)^SHOW\s+DATABASES(\s+HISTORY)?(?:\s+LIKE\s+'((?:[^']|'')*)')?$`)
+	showSchemasRe   = regexp.MustCompile(`(?is)^SHOW\s+SCHEMAS(\s+HISTORY)?(?:\s+LIKE\s+'((?:[^']|'')*)')?\s+IN\s+DATABASE\s+(\S+)$`)
+	showGrantsDbRe  = regexp.MustCompile(`(?is)^SHOW\s+GRANTS\s+ON\s+DATABASE\s+(\S+)$`)
+	showGrantsSchRe = regexp.MustCompile(`(?is)^SHOW\s+GRANTS\s+ON\s+SCHEMA\s+([^\s.]+)\.(\S+)$`)
+	showFutureSchRe = regexp.MustCompile(`(?is)^SHOW\s+FUTURE\s+GRANTS\s+IN\s+SCHEMA\s+([^\s.]+)\.(\S+)$`)
+	showObjectsRe   = regexp.MustCompile(`(?is)^SHOW\s+OBJECTS\s+IN\s+SCHEMA\s+([^\s.]+)\.(\S+)$`)
+	showDbRolesRe   = regexp.MustCompile(`(?is)^SHOW\s+DATABASE\s+ROLES\s+IN\s+DATABASE\s+(\S+)$`)
+	showGrantsOfRe  = regexp.MustCompile(`(?is)^SHOW\s+GRANTS\s+OF\s+DATABASE\s+ROLE\s+([^\s.]+)\.(\S+)$`)
+	showGrantsToRe  = regexp.MustCompile(`(?is)^SHOW\s+GRANTS\s+TO\s+ROLE\s+(\S+)$`)
+	showRoleOfRe    = regexp.MustCompile(`(?is)^SHOW\s+GRANTS\s+OF\s+ROLE\s+(\S+)$`)
+	showGrantsObjRe = regexp.MustCompile(`(?is)^SHOW\s+GRANTS\s+ON\s+(\w+(?:\s+\w+)?)\s+([^\s.]+)\.([^\s.]+)\.(\S+)$`)
+	showParamsDbRe  = regexp.MustCompile(`(?is)^SHOW\s+PARAMETERS\s+IN\s+DATABASE\s+(\S+)$`)
+	showParamsSchRe = regexp.MustCompile(`(?is)^SHOW\s+PARAMETERS\s+IN\s+SCHEMA\s+([^\s.]+)\.(\S+)$`)
+	tagRefsRe       = regexp.MustCompile(`(?is)^SELECT\s+\*\s+FROM\s+TABLE\(\s*\S+\.INFORMATION_SCHEMA\.TAG_REFERENCES\(\s*'([^']*)'\s*,\s*'([^']*)'\s*\)\s*\)$`)
+)
+
+var (
+	fakeDatabaseColumns = []string{
+		"created_on", "name", "is_default", "is_current", "origin", "owner",
+		"comment", "options", "retention_time", "kind", "budget", "owner_role_type", "resource_group",
+	}
+	fakeSchemaColumns = []string{
+		"created_on", "name", "is_default", "is_current", "database_name", "owner",
+		"comment", "options", "retention_time", "owner_role_type", "budget", "resource_group",
+	}
+	fakeGrantColumns = []string{
+		"created_on", "privilege", "granted_on", "name", "granted_to",
+		"grantee_name", "grant_option", "granted_by",
+	}
+	fakeFutureGrantColumns = []string{
+		"created_on", "privilege", "grant_on", "name", "grant_to",
+		"grantee_name", "grant_option",
+	}
+	fakeDatabaseRoleColumns = []string{
+		"created_on", "name", "is_default", "is_current", "is_inherited", "granted_to_roles",
+		"granted_to_database_roles", "granted_database_roles", "owner", "comment", "owner_role_type",
+	}
+	fakeGrantsOfColumns = []string{
+		"created_on", "role", "granted_to", "grantee_name", "granted_by",
+	}
+	fakeParameterColumns = []string{
+		"key", "value", "default", "level", "description", "type",
+	}
+	fakeTagReferenceColumns = []string{
+		"TAG_DATABASE", "TAG_SCHEMA", "TAG_NAME", "TAG_VALUE", "LEVEL",
+		"OBJECT_DATABASE", "OBJECT_SCHEMA", "OBJECT_NAME", "DOMAIN", "COLUMN_NAME",
+	}
+	fakeObjectColumns = []string{
+		"created_on", "name", "database_name", "schema_name", "kind", "comment",
+		"cluster_by", "rows", "bytes", "owner", "retention_time", "owner_role_type",
+	}
+)
+
+// query executes a single statement against the catalog.
+func (c *fakeCatalog) query(stmt string) (*fakeRows, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stmt = strings.TrimSuffix(strings.TrimSpace(stmt), ";")
+	stmt = strings.TrimSpace(stmt)
+
+	if m := showDatabasesRe.FindStringSubmatch(stmt); m != nil {
+		return c.showDatabases(unescapeLike(m[2]), m[1] != ""), nil
+	}
+	if m := showSchemasRe.FindStringSubmatch(stmt); m != nil {
+		return c.showSchemas(unescapeLike(m[2]), m[3], m[1] != "")
+	}
+	if m := showGrantsDbRe.FindStringSubmatch(stmt); m != nil {
+		return c.showDatabaseGrants(m[1])
+	}
+	if m := showGrantsSchRe.FindStringSubmatch(stmt); m != nil {
+		return c.showSchemaGrants(m[1], m[2])
+	}
+	if m := showFutureSchRe.FindStringSubmatch(stmt); m != nil {
+		return c.showSchemaFutureGrants(m[1], m[2])
+	}
+	if m := showDbRolesRe.FindStringSubmatch(stmt); m != nil {
+		return c.showDatabaseRoles(m[1])
+	}
+	if m := showGrantsOfRe.FindStringSubmatch(stmt); m != nil {
+		return c.showGrantsOfDatabaseRole(m[1], m[2])
+	}
+	if m := showGrantsToRe.FindStringSubmatch(stmt); m != nil {
+		return c.showGrantsToRole(m[1]), nil
+	}
+	if m := showRoleOfRe.FindStringSubmatch(stmt); m != nil {
+		return c.showGrantsOfRole(m[1]), nil
+	}
+	if m := showObjectsRe.FindStringSubmatch(stmt); m != nil {
+		return c.showObjects(m[1], m[2])
+	}
+	if m := showGrantsObjRe.FindStringSubmatch(stmt); m != nil {
+		return c.showObjectGrants(m[1], m[2], m[3], m[4])
+	}
+	if m := showParamsDbRe.FindStringSubmatch(stmt); m != nil {
+		return c.showDatabaseParameters(m[1])
+	}
+	if m := showParamsSchRe.FindStringSubmatch(stmt); m != nil {
+		return c.showSchemaParameters(m[1], m[2])
+	}
+	if m := tagRefsRe.FindStringSubmatch(stmt); m != nil {
+		return c.tagReferences(m[1], m[2])
+	}
+	return nil, fmt.Errorf("snowflake-fake: unsupported statement: %s", stmt)
+}
+
+func unescapeLike(s string) string {
+	return strings.ReplaceAll(s, "''", "'")
+}
+
+// showDatabases lists the live databases, and with history the dropped ones
+// too, with an extra dropped_on column
+func (c *fakeCatalog) showDatabases(pattern string, history bool) *fakeRows {
+	rows := &fakeRows{columns: fakeDatabaseColumns}
+	if history {
+		rows.columns = append(append([]string{}, fakeDatabaseColumns...), "dropped_on")
+	}
+	for _, d := range c.databases {
+		if pattern != "" && !likeMatch(pattern, d.Name) {
+			continue
+		}
+		if !d.DroppedOn.IsZero() && !history {
+			continue
+		}
+		var options []string
+		if d.IsTransient {
+			options = append(options, "TRANSIENT")
+		}
+		kind := d.Kind
+		if kind == "" {
+			kind = "STANDARD"
+		}
+		row := []driver.Value{
+			d.CreatedOn, d.Name, "N", "N", d.Origin, d.Owner,
+			d.Comment, strings.Join(options, ", "), fmt.Sprintf("%d", d.RetentionTime), kind, nullIfEmpty(d.Budget), "ROLE",
+			nullIfEmpty(d.ResourceGroup),
+		}
+		if history {
+			row = append(row, nullIfZero(d.DroppedOn))
+		}
+		rows.values = append(rows.values, row)
+	}
+	return rows
+}
+
+func (c *fakeCatalog) showSchemas(pattern, databaseName string, history bool) (*fakeRows, error) {
+	d := c.findDatabase(databaseName)
+	if d == nil {
+		return nil, fmt.Errorf("snowflake-fake: database '%s' does not exist or not authorized", databaseName)
+	}
+
+	rows := &fakeRows{columns: fakeSchemaColumns}
+	if history {
+		rows.columns = append(append([]string{}, fakeSchemaColumns...), "dropped_on")
+	}
+	for _, s := range d.Schemas {
+		if pattern != "" && !likeMatch(pattern, s.Name) {
+			continue
+		}
+		if !s.DroppedOn.IsZero() && !history {
+			continue
+		}
+		var options []string
+		if s.IsTransient {
+			options = append(options, "TRANSIENT")
+		}
+		if s.IsManagedAccess {
+			options = append(options, "MANAGED ACCESS")
+		}
+		row := []driver.Value{
+			s.CreatedOn, s.Name, "N", "N", d.Name, s.Owner,
+			s.Comment, strings.Join(options, ", "), fmt.Sprintf("%d", s.RetentionTime), "ROLE", nullIfEmpty(s.Budget),
+			nullIfEmpty(s.ResourceGroup),
+		}
+		if history {
+			row = append(row, nullIfZero(s.DroppedOn))
+		}
+		rows.values = append(rows.values, row)
+	}
+	return rows, nil
+}
+
+// nullIfEmpty reports an unset optional column as NULL
+func nullIfEmpty(s string) driver.Value {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nullIfZero reports an unset timestamp column as NULL
+func nullIfZero(t time.Time) driver.Value {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+func (c *fakeCatalog) showDatabaseGrants(databaseName string) (*fakeRows, error) {
+	d := c.findDatabase(databaseName)
+	if d == nil {
+		return nil, fmt.Errorf("snowflake-fake: database '%s' does not exist or not authorized", databaseName)
+	}
+	return grantRows(d.CreatedOn, "DATABASE", d.Name, d.Owner, d.Grants), nil
+}
+
+func (c *fakeCatalog) showSchemaGrants(databaseName, schemaName string) (*fakeRows, error) {
+	d := c.findDatabase(databaseName)
+	if d == nil {
+		return nil, fmt.Errorf("snowflake-fake: database '%s' does not exist or not authorized", databaseName)
+	}
+	s := d.findSchema(schemaName)
+	if s == nil {
+		return nil, fmt.Errorf("snowflake-fake: schema '%s.%s' does not exist or not authorized", databaseName, schemaName)
+	}
+	return grantRows(s.CreatedOn, "SCHEMA", d.Name+"."+s.Name, s.Owner, s.Grants), nil
+}
+
+func (c *fakeCatalog) showSchemaFutureGrants(databaseName, schemaName string) (*fakeRows, error) {
+	d := c.findDatabase(databaseName)
+	if d == nil {
+		return nil, fmt.Errorf("snowflake-fake: database '%s' does not exist or not authorized", databaseName)
+	}
+	s := d.findSchema(schemaName)
+	if s == nil {
+		return nil, fmt.Errorf("snowflake-fake: schema '%s.%s' does not exist or not authorized", databaseName, schemaName)
+	}
+
+	rows := &fakeRows{columns: fakeFutureGrantColumns}
+	for _, g := range s.FutureGrants {
+		rows.values = append(rows.values, []driver.Value{
+			s.CreatedOn, g.Privilege, g.ObjectType, fmt.Sprintf("%s.%s.<%s>", d.Name, s.Name, g.ObjectType), "ROLE", g.Grantee, "false",
+		})
+	}
+	return rows, nil
+}
+
+func (c *fakeCatalog) showDatabaseRoles(databaseName string) (*fakeRows, error) {
+	d := c.findDatabase(databaseName)
+	if d == nil {
+		return nil, fmt.Errorf("snowflake-fake: database '%s' does not exist or not authorized", databaseName)
+	}
+
+	rows := &fakeRows{columns: fakeDatabaseRoleColumns}
+	for _, r := range d.DatabaseRoles {
+		rows.values = append(rows.values, []driver.Value{
+			r.CreatedOn, r.Name, "N", "N", "N", fmt.Sprintf("%d", len(r.GrantedTo)),
+			"0", "0", r.Owner, r.Comment, "ROLE",
+		})
+	}
+	return rows, nil
+}
+
+func (c *fakeCatalog) showGrantsOfDatabaseRole(databaseName, roleName string) (*fakeRows, error) {
+	d := c.findDatabase(databaseName)
+	if d == nil {
+		return nil, fmt.Errorf("snowflake-fake: database '%s' does not exist or not authorized", databaseName)
+	}
+	r := d.findDatabaseRole(roleName)
+	if r == nil {
+		return nil, fmt.Errorf("snowflake-fake: database role '%s.%s' does not exist or not authorized", databaseName, roleName)
+	}
+
+	rows := &fakeRows{columns: fakeGrantsOfColumns}
+	for _, grantee := range r.GrantedTo {
+		rows.values = append(rows.values, []driver.Value{
+			r.CreatedOn, d.Name + "." + r.Name, "ROLE", grantee, r.Owner,
+		})
+	}
+	return rows, nil
+}
+
+// showGrantsToRole lists every privilege granted to an account role across the
+// catalog, plus USAGE on each role granted to it.
+func (c *fakeCatalog) showGrantsToRole(role string) *fakeRows {
+	rows := &fakeRows{columns: fakeGrantColumns}
+	add := func(createdOn time.Time, grantedOn, name, owner string, grants []fakeGrant) {
+		for _, g := range grants {
+			if g.GrantedTo == "ROLE" && identifierEqual(g.Grantee, role) {
+				rows.values = append(rows.values, []driver.Value{
+					createdOn, g.Privilege, grantedOn, name, "ROLE", g.Grantee, "false", owner,
+				})
+			}
+		}
+	}
+
+	for _, d := range c.databases {
+		add(d.CreatedOn, "DATABASE", d.Name, d.Owner, d.Grants)
+		for _, s := range d.Schemas {
+			add(s.CreatedOn, "SCHEMA", d.Name+"."+s.Name, s.Owner, s.Grants)
+			for _, o := range s.Objects {
+				add(o.CreatedOn, o.Kind, d.Name+"."+s.Name+"."+o.Name, o.Owner, o.Grants)
+			}
+		}
+	}
+	for _, g := range c.roleGrants {
+		if identifierEqual(g.Parent, role) {
+			rows.values = append(rows.values, []driver.Value{
+				time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), "USAGE", "ROLE", g.Role, "ROLE", g.Parent, "false", "USERADMIN",
+			})
+		}
+	}
+	return rows
+}
+
+// showGrantsOfRole lists the account roles a role has been granted to.
+func (c *fakeCatalog) showGrantsOfRole(role string) *fakeRows {
+	rows := &fakeRows{columns: fakeGrantsOfColumns}
+	for _, g := range c.roleGrants {
+		if identifierEqual(g.Role, role) {
+			rows.values = append(rows.values, []driver.Value{
+				time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), g.Role, "ROLE", g.Parent, "USERADMIN",
+			})
+		}
+	}
+	return rows
+}
+
+func (c *fakeCatalog) showObjects(databaseName, schemaName string) (*fakeRows, error) {
+	d, s, err := c.lookupSchema(databaseName, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := &fakeRows{columns: fakeObjectColumns}
+	for _, o := range s.Objects {
+		rows.values = append(rows.values, []driver.Value{
+			o.CreatedOn, o.Name, d.Name, s.Name, o.Kind, "",
+			"", "0", "0", o.Owner, fmt.Sprintf("%d", s.RetentionTime), "ROLE",
+		})
+	}
+	return rows, nil
+}
+
+func (c *fakeCatalog) showObjectGrants(kind, databaseName, schemaName, objectName string) (*fakeRows, error) {
+	d, s, err := c.lookupSchema(databaseName, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	kind = strings.ToUpper(strings.Join(strings.Fields(kind), "_"))
+	o := s.findObject(kind, objectName)
+	if o == nil {
+		return nil, fmt.Errorf("snowflake-fake: %s '%s.%s.%s' does not exist or not authorized", kind, databaseName, schemaName, objectName)
+	}
+	return grantRows(o.CreatedOn, kind, d.Name+"."+s.Name+"."+o.Name, o.Owner, o.Grants), nil
+}
+
+// fakeParameter is an account-level parameter default reported by SHOW
+// PARAMETERS when neither the database nor the schema sets it.
+type fakeParameter struct {
+	Key         string
+	Default     string
+	Type        string
+	Description string
+}
+
+var fakeParameters = []fakeParameter{
+	{"CATALOG", "", "STRING", "Name of the catalog integration to use for iceberg tables"},
+	{"DATA_RETENTION_TIME_IN_DAYS", "1", "NUMBER", "number of days to retain the old version of deleted/updated data"},
+	{"DEFAULT_DDL_COLLATION", "", "STRING", "Collation that is used for all the new columns created by the DDL statements"},
+	{"EVENT_TABLE", "", "STRING", "Event table for logging and tracing"},
+	{"EXTERNAL_VOLUME", "", "STRING", "Name of an external volume that will be used for persisted Iceberg metadata and data files."},
+	{"LOG_LEVEL", "OFF", "STRING", "Severity level of messages that should be ingested and made available in the active event table"},
+	{"MAX_DATA_EXTENSION_TIME_IN_DAYS", "14", "NUMBER", "Maximum number of days to extend data retention beyond the retention period to prevent a stream becoming stale"},
+	{"REPLACE_INVALID_CHARACTERS", "false", "BOOLEAN", "Whether to replace invalid characters in Iceberg tables with the Unicode replacement character"},
+	{"STORAGE_SERIALIZATION_POLICY", "OPTIMIZED", "STRING", "Storage serialization policy used for managed Iceberg table"},
+	{"TRACE_LEVEL", "OFF", "STRING", "Trace level value determines what trace events are ingested"},
+}
+
+// fakeParameterValue is a resolved parameter and the level it was set at
+type fakeParameterValue struct {
+	Value string
+	Level string
+}
+
+// databaseParameters resolves each parameter for a database to its value and
+// level: DATABASE when set on it, empty when the account default applies.
+// Retention comes from RetentionTime and counts as set when it is not the
+// default.
+func databaseParameters(d *fakeDatabase) map[string]fakeParameterValue {
+	resolved := map[string]fakeParameterValue{}
+	for _, p := range fakeParameters {
+		resolved[p.Key] = fakeParameterValue{p.Default, ""}
+		if v, ok := d.Parameters[p.Key]; ok {
+			resolved[p.Key] = fakeParameterValue{v, "DATABASE"}
+		}
+	}
+	if retention := fmt.Sprintf("%d", d.RetentionTime); retention != resolved["DATA_RETENTION_TIME_IN_DAYS"].Value {
+		resolved["DATA_RETENTION_TIME_IN_DAYS"] = fakeParameterValue{retention, "DATABASE"}
+	}
+	return resolved
+}
+
+func (c *fakeCatalog) showDatabaseParameters(databaseName string) (*fakeRows, error) {
+	d := c.findDatabase(databaseName)
+	if d == nil {
+		return nil, fmt.Errorf("snowflake-fake: database '%s' does not exist or not authorized", databaseName)
+	}
+	return parameterRows(databaseParameters(d)), nil
+}
+
+// showSchemaParameters reports schema parameters, inheriting the database
+// value and level unless the schema sets its own (level SCHEMA).
+func (c *fakeCatalog) showSchemaParameters(databaseName, schemaName string) (*fakeRows, error) {
+	d, s, err := c.lookupSchema(databaseName, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	resolved := databaseParameters(d)
+	for key, v := range s.Parameters {
+		resolved[key] = fakeParameterValue{v, "SCHEMA"}
+	}
+	if s.RetentionTime != d.RetentionTime {
+		resolved["DATA_RETENTION_TIME_IN_DAYS"] = fakeParameterValue{fmt.Sprintf("%d", s.RetentionTime), "SCHEMA"}
+	}
+	return parameterRows(resolved), nil
+}
+
+func parameterRows(resolved map[string]fakeParameterValue) *fakeRows {
+	rows := &fakeRows{columns: fakeParameterColumns}
+	for _, p := range fakeParameters {
+		r := resolved[p.Key]
+		rows.values = append(rows.values, []driver.Value{
+			p.Key, r.Value, p.Default, r.Level, p.Description, p.Type,
+		})
+	}
+	return rows
+}
+
+// tagReferences answers the TAG_REFERENCES table function for a database or
+// a schema. Schemas also report the database tags they inherit, at level
+// DATABASE, unless they set the same tag themselves.
+func (c *fakeCatalog) tagReferences(objectName, domain string) (*fakeRows, error) {
+	rows := &fakeRows{columns: fakeTagReferenceColumns}
+	add := func(tags map[string]string, level string, objectDatabase driver.Value, name string, skip map[string]string) {
+		keys := make([]string, 0, len(tags))
+		for k := range tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, tag := range keys {
+			if _, ok := skip[tag]; ok {
+				continue
+			}
+			parts := strings.SplitN(tag, ".", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			rows.values = append(rows.values, []driver.Value{
+				parts[0], parts[1], parts[2], tags[tag], level,
+				objectDatabase, nil, name, strings.ToUpper(domain), nil,
+			})
+		}
+	}
+
+	switch strings.ToUpper(domain) {
+	case "DATABASE":
+		d := c.findDatabase(objectName)
+		if d == nil {
+			return nil, fmt.Errorf("snowflake-fake: database '%s' does not exist or not authorized", objectName)
+		}
+		add(d.Tags, "DATABASE", nil, d.Name, nil)
+	case "SCHEMA":
+		parts := strings.SplitN(objectName, ".", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("snowflake-fake: invalid schema name '%s'", objectName)
+		}
+		d, s, err := c.lookupSchema(parts[0], parts[1])
+		if err != nil {
+			return nil, err
+		}
+		add(s.Tags, "SCHEMA", d.Name, s.Name, nil)
+		add(d.Tags, "DATABASE", d.Name, s.Name, s.Tags)
+	default:
+		return nil, fmt.Errorf("snowflake-fake: unsupported TAG_REFERENCES domain '%s'", domain)
+	}
+	return rows, nil
+}
+
+// lookupSchema resolves a database and schema, returning the error Snowflake
+// reports when either does not exist.
+func (c *fakeCatalog) lookupSchema(databaseName, schemaName string) (*fakeDatabase, *fakeSchema, error) {
+	d := c.findDatabase(databaseName)
+	if d == nil {
+		return nil, nil, fmt.Errorf("snowflake-fake: database '%s' does not exist or not authorized", databaseName)
+	}
+	s := d.findSchema(schemaName)
+	if s == nil {
+		return nil, nil, fmt.Errorf("snowflake-fake: schema '%s.%s' does not exist or not authorized", databaseName, schemaName)
+	}
+	return d, s, nil
+}
+
+// grantRows renders SHOW GRANTS ON output, including the implicit OWNERSHIP
+// grant that Snowflake always lists for the owning role.
+func grantRows(createdOn time.Time, grantedOn, name, owner string, grants []fakeGrant) *fakeRows {
+	rows := &fakeRows{columns: fakeGrantColumns}
+	rows.values = append(rows.values, []driver.Value{
+		createdOn, "OWNERSHIP", grantedOn, name, "ROLE", owner, "true", owner,
+	})
+	for _, g := range grants {
+		rows.values = append(rows.values, []driver.Value{
+			createdOn, g.Privilege, grantedOn, name, g.GrantedTo, g.Grantee, "false", owner,
+		})
+	}
+	return rows
+}
+
+// fakeDriver implements driver.Driver. The DSN names the catalog to use.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	return &fakeConn{catalog: getFakeCatalog(dsn)}, nil
+}
+
+type fakeConn struct {
+	catalog *fakeCatalog
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("snowflake-fake: transactions are not supported")
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) > 0 {
+		return nil, fmt.Errorf("snowflake-fake: bind arguments are not supported")
+	}
+	return c.catalog.query(query)
+}
+
+func (c *fakeConn) Ping(context.Context) error { return nil }
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return 0 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("snowflake-fake: statements without results are not supported")
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return s.conn.catalog.query(s.query)
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	pos     int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.pos])
+	r.pos++
+	return nil
+}
+
+// fakeEndpointLoginPath and fakeEndpointQueryPath are the REST paths the
+// Snowflake drivers post logins and statements to.
+const (
+	fakeEndpointLoginPath = "/session/v1/login-request"
+	fakeEndpointQueryPath = "/queries/v1/query-request"
+)
+
+// startFakeSnowflakeEndpoint serves the Snowflake login and query REST
+// endpoints over plain HTTP on a local port. Every login succeeds and every
+// statement returns a single row holding 1, which is what the driver's ping
+// expects. Pointing the provider at it lets terraform plan configure the
+// provider with placeholder credentials and no Snowflake account.
+func startFakeSnowflakeEndpoint(t *testing.T) *url.URL {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(fakeEndpointLoginPath, func(w http.ResponseWriter, r *http.Request) {
+		writeFakeEndpointResponse(w, map[string]interface{}{
+			"token":                   "fake-session-token",
+			"validityInSeconds":       3600,
+			"masterToken":             "fake-master-token",
+			"masterValidityInSeconds": 14400,
+			"sessionId":               1,
+			"serverVersion":           "fake",
+		})
+	})
+	mux.HandleFunc(fakeEndpointQueryPath, func(w http.ResponseWriter, r *http.Request) {
+		writeFakeEndpointResponse(w, map[string]interface{}{
+			"queryId":           "01fake00-0000-0000-0000-000000000000",
+			"queryResultFormat": "json",
+			"rowtype": []map[string]interface{}{
+				{"name": "1", "type": "fixed", "precision": 1, "scale": 0, "nullable": false},
+			},
+			"rowset":   [][]string{{"1"}},
+			"total":    1,
+			"returned": 1,
+		})
+	})
+	// Heartbeats, telemetry and session close only need to succeed
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeFakeEndpointResponse(w, map[string]interface{}{})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return endpoint
+}
+
+func writeFakeEndpointResponse(w http.ResponseWriter, data map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":    data,
+		"code":    nil,
+		"message": nil,
+		"success": true,
+	})
+}
+
+// placeholderPrivateKey generates a throwaway RSA key in PKCS8 PEM form for
+// key-pair authentication against the fake endpoint.
+func placeholderPrivateKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
@@ -0,0 +1,356 @@
+// File: test/fake_snowflake_test.go
+package test
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/snowflakedb/gosnowflake"
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+)
+
+// TestFakeSnowflakeDatabaseHelpers exercises the database helpers against the
+// in-memory driver
+func TestFakeSnowflakeDatabaseHelpers(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	analytics := catalog.addDatabase(fakeDatabase{
+		Name:          "TT_ANALYTICS",
+		Comment:       "Analytics database",
+		RetentionTime: 7,
+	})
+	catalog.addDatabase(fakeDatabase{Name: "TT_ANALYTICS_ARCHIVE", RetentionTime: 1})
+	catalog.grantOnDatabase(analytics, "USAGE", "TT_READER")
+	catalog.grantOnDatabase(analytics, "MONITOR", "TT_OPS")
+	catalog.grantOnDatabase(analytics, "CREATE SCHEMA", "TT_OPS")
+	catalog.grantOnDatabase(analytics, "MODIFY", "TT_OPS")
+	catalog.grantOnDatabase(analytics, "CREATE DATABASE ROLE", "TT_OPS")
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	require.True(t, databaseExists(t, db, "TT_ANALYTICS"))
+	require.True(t, databaseExists(t, db, "tt_analytics"), "LIKE patterns are case-insensitive")
+	require.False(t, databaseExists(t, db, "TT_MISSING"))
+
+	props := fetchDatabaseProps(t, db, "TT_ANALYTICS")
+	require.Equal(t, "TT_ANALYTICS", props.Name)
+	require.Equal(t, "Analytics database", props.Comment)
+	require.Equal(t, 7, props.DataRetentionTimeInDays)
+
+	grants := fetchDatabaseGrants(t, db, "TT_ANALYTICS", "TT_READER")
+	require.Len(t, grants, 1)
+	require.True(t, hasPrivilege(grants, "USAGE"))
+	require.Equal(t, "DATABASE", grants[0].GrantedOn)
+	require.Equal(t, "ROLE", grants[0].GrantedTo)
+
+	require.True(t, hasPrivilege(fetchDatabaseGrants(t, db, "TT_ANALYTICS", "SYSADMIN"), "OWNERSHIP"))
+	require.Empty(t, fetchDatabaseGrants(t, db, "TT_ANALYTICS", "TT_WRITER"))
+
+	opsGrants := fetchDatabaseGrants(t, db, "TT_ANALYTICS", "TT_OPS")
+	require.Len(t, opsGrants, 4)
+	for _, privilege := range []string{"MONITOR", "CREATE SCHEMA", "MODIFY", "CREATE DATABASE ROLE"} {
+		require.True(t, hasPrivilege(opsGrants, privilege), "Expected %s for TT_OPS", privilege)
+	}
+	require.False(t, hasPrivilege(opsGrants, "USAGE"))
+}
+
+// TestFakeSnowflakeShowColumns verifies DatabaseProps and SchemaProps expose
+// every SHOW DATABASES and SHOW SCHEMAS column, including NULLs
+func TestFakeSnowflakeShowColumns(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	createdOn := time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)
+	local := catalog.addDatabase(fakeDatabase{Name: "TT_LOCAL", RetentionTime: 1, CreatedOn: createdOn, Budget: "TT_BUDGET"})
+	catalog.addSchema(local, fakeSchema{Name: "RAW", RetentionTime: 1, IsManagedAccess: true, ResourceGroup: "TT_GROUP"})
+	catalog.addDatabase(fakeDatabase{Name: "TT_SHARED", Owner: "ACCOUNTADMIN", Origin: "PROVIDER.SALES_SHARE", Kind: "IMPORTED DATABASE"})
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	props := fetchDatabaseProps(t, db, "TT_LOCAL")
+	require.Equal(t, createdOn, props.CreatedOn)
+	require.False(t, props.IsDefault)
+	require.False(t, props.IsCurrent)
+	require.Empty(t, props.Origin)
+	require.Equal(t, "SYSADMIN", props.Owner)
+	require.Equal(t, "STANDARD", props.Kind)
+	require.Equal(t, ptr("TT_BUDGET"), props.Budget)
+	require.Equal(t, "ROLE", props.OwnerRoleType)
+	require.Nil(t, props.ResourceGroup)
+	require.False(t, props.IsShared())
+
+	shared := fetchDatabaseProps(t, db, "TT_SHARED")
+	require.Equal(t, "PROVIDER.SALES_SHARE", shared.Origin)
+	require.Equal(t, "IMPORTED DATABASE", shared.Kind)
+	require.Equal(t, "ACCOUNTADMIN", shared.Owner)
+	require.True(t, shared.IsShared())
+
+	schema := fetchSchemaProps(t, db, "TT_LOCAL", "RAW")
+	require.Equal(t, createdOn, schema.CreatedOn, "schemas default to the database creation time")
+	require.Equal(t, "MANAGED ACCESS", schema.Options)
+	require.Equal(t, "SYSADMIN", schema.Owner)
+	require.Equal(t, "ROLE", schema.OwnerRoleType)
+	require.Nil(t, schema.Budget)
+	require.Equal(t, ptr("TT_GROUP"), schema.ResourceGroup)
+}
+
+// TestFakeSnowflakeParameterHelpers verifies parameter values and levels for
+// a database and for schemas that set or inherit them
+func TestFakeSnowflakeParameterHelpers(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	dw := catalog.addDatabase(fakeDatabase{Name: "TT_DW", RetentionTime: 7, Parameters: map[string]string{"LOG_LEVEL": "WARN"}})
+	catalog.addSchema(dw, fakeSchema{Name: "RAW", RetentionTime: 7})
+	catalog.addSchema(dw, fakeSchema{Name: "CURATED", RetentionTime: 30, Parameters: map[string]string{"LOG_LEVEL": "ERROR"}})
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	dbParams := fetchDatabaseParameters(t, db, "TT_DW")
+	requireParameter(t, dbParams, "DATA_RETENTION_TIME_IN_DAYS", "7", "DATABASE")
+	requireParameter(t, dbParams, "log_level", "WARN", "DATABASE")
+	requireParameter(t, dbParams, "MAX_DATA_EXTENSION_TIME_IN_DAYS", "14", "")
+	retention, err := dbParams["DATA_RETENTION_TIME_IN_DAYS"].IntValue()
+	require.NoError(t, err)
+	require.Equal(t, 7, retention)
+	require.Equal(t, "NUMBER", dbParams["DATA_RETENTION_TIME_IN_DAYS"].Type)
+
+	raw := fetchSchemaParameters(t, db, "TT_DW", "RAW")
+	requireParameter(t, raw, "DATA_RETENTION_TIME_IN_DAYS", "7", "DATABASE")
+	requireParameter(t, raw, "LOG_LEVEL", "WARN", "DATABASE")
+	require.False(t, raw["LOG_LEVEL"].SetOn("SCHEMA"), "RAW inherits LOG_LEVEL")
+
+	curated := fetchSchemaParameters(t, db, "TT_DW", "CURATED")
+	requireParameter(t, curated, "DATA_RETENTION_TIME_IN_DAYS", "30", "SCHEMA")
+	requireParameter(t, curated, "LOG_LEVEL", "ERROR", "SCHEMA")
+	require.True(t, curated["LOG_LEVEL"].SetOn("schema"))
+	requireParameter(t, curated, "TRACE_LEVEL", "OFF", "")
+
+	_, err = db.Query("SHOW PARAMETERS IN SCHEMA TT_DW.MISSING;")
+	require.Error(t, err)
+}
+
+// TestFakeSnowflakeSchemaHelpers exercises the schema helpers against the
+// in-memory driver
+func TestFakeSnowflakeSchemaHelpers(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	warehouse := catalog.addDatabase(fakeDatabase{Name: "TT_DW", RetentionTime: 1})
+	curated := catalog.addSchema(warehouse, fakeSchema{
+		Name:            "CURATED",
+		Comment:         "Curated business data",
+		RetentionTime:   14,
+		IsManagedAccess: true,
+	})
+	catalog.addSchema(warehouse, fakeSchema{Name: "STAGING", IsTransient: true, RetentionTime: 1})
+	catalog.grantOnSchema(curated, "USAGE", "TT_READER")
+	catalog.grantOnSchema(curated, "CREATE TABLE", "TT_WRITER")
+	catalog.grantFutureInSchema(curated, "TABLE", "SELECT", "TT_READER")
+	catalog.grantFutureInSchema(curated, "MATERIALIZED_VIEW", "SELECT", "TT_READER")
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	require.True(t, schemaExists(t, db, "TT_DW", "CURATED"))
+	require.False(t, schemaExists(t, db, "TT_DW", "RAW"))
+
+	props := fetchSchemaProps(t, db, "TT_DW", "CURATED")
+	require.Equal(t, "CURATED", props.Name)
+	require.Equal(t, "TT_DW", props.DatabaseName)
+	require.Equal(t, "Curated business data", props.Comment)
+	require.Equal(t, 14, props.DataRetentionTimeInDays)
+	require.True(t, props.IsManagedAccess)
+
+	require.False(t, fetchSchemaProps(t, db, "TT_DW", "STAGING").IsManagedAccess)
+
+	readerGrants := fetchSchemaGrants(t, db, "TT_DW", "CURATED", "TT_READER")
+	require.True(t, hasPrivilege(readerGrants, "USAGE"))
+	require.False(t, hasPrivilege(readerGrants, "CREATE TABLE"))
+
+	writerGrants := fetchSchemaGrants(t, db, "TT_DW", "CURATED", "TT_WRITER")
+	require.True(t, hasPrivilege(writerGrants, "CREATE TABLE"))
+	require.Equal(t, "TT_DW.CURATED", writerGrants[0].Name)
+
+	futureGrants := fetchSchemaFutureGrants(t, db, "TT_DW", "CURATED", "TT_READER")
+	require.Len(t, futureGrants, 2)
+	require.True(t, hasFutureGrant(futureGrants, "TABLES", "SELECT"))
+	require.True(t, hasFutureGrant(futureGrants, "materialized views", "SELECT"))
+	require.False(t, hasFutureGrant(futureGrants, "VIEWS", "SELECT"))
+	require.Equal(t, "TT_DW.CURATED.<TABLE>", futureGrants[0].Name)
+	require.Empty(t, fetchSchemaFutureGrants(t, db, "TT_DW", "CURATED", "TT_WRITER"))
+}
+
+// TestFakeSnowflakeObjectHelpers exercises the all-objects coverage helpers
+// against the in-memory driver
+func TestFakeSnowflakeObjectHelpers(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	legacy := catalog.addDatabase(fakeDatabase{Name: "TT_LEGACY", RetentionTime: 1})
+	raw := catalog.addSchema(legacy, fakeSchema{Name: "RAW", RetentionTime: 1})
+	orders := catalog.addObject(raw, fakeObject{Name: "ORDERS", Kind: "TABLE"})
+	customers := catalog.addObject(raw, fakeObject{Name: "CUSTOMERS", Kind: "TABLE"})
+	report := catalog.addObject(raw, fakeObject{Name: "DAILY_REPORT", Kind: "VIEW"})
+	catalog.grantOnObject(orders, "SELECT", "TT_READER")
+	catalog.grantOnObject(customers, "SELECT", "TT_READER")
+	catalog.grantOnObject(orders, "INSERT", "TT_LOADER")
+	catalog.grantOnObject(report, "SELECT", "TT_READER")
+
+	rawCfg := dbconfig.NewSchemaConfig("RAW")
+	rawCfg.AllObjectsGrants = map[string]map[string][]string{
+		"TABLES": {"SELECT": {"TT_READER"}},
+		"views":  {"select": {"TT_READER"}},
+	}
+	legacyCfg := dbconfig.NewDatabaseConfig("TT_LEGACY")
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	objects := fetchSchemaObjects(t, db, "TT_LEGACY", "RAW")
+	require.Len(t, objects, 3)
+	require.Equal(t, "TABLE", objects[0].Kind)
+	require.Equal(t, "RAW", objects[0].SchemaName)
+
+	requireAllObjectsGranted(t, db, legacyCfg, rawCfg)
+
+	missing, err := objectsMissingPrivilege(db, "TT_LEGACY", "RAW", "TABLES", "INSERT", "TT_LOADER")
+	require.NoError(t, err)
+	require.Equal(t, []string{"TT_LEGACY.RAW.CUSTOMERS"}, missing)
+
+	missing, err = objectsMissingPrivilege(db, "TT_LEGACY", "RAW", "VIEWS", "SELECT", "TT_WRITER")
+	require.NoError(t, err)
+	require.Equal(t, []string{"TT_LEGACY.RAW.DAILY_REPORT"}, missing)
+}
+
+// TestFakeSnowflakeDatabaseRoleHelpers exercises the database role helpers
+// and DATABASE_ROLE grantee parsing against the in-memory driver
+func TestFakeSnowflakeDatabaseRoleHelpers(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	sales := catalog.addDatabase(fakeDatabase{Name: "TT_SALES", RetentionTime: 1})
+	raw := catalog.addSchema(sales, fakeSchema{Name: "RAW", RetentionTime: 1})
+	read := catalog.addDatabaseRole(sales, fakeDatabaseRole{Name: "SALES_READ", Comment: "Read sales"})
+	catalog.grantDatabaseRole(read, "TT_ANALYST")
+	catalog.grantOnDatabaseToDatabaseRole(sales, "USAGE", "SALES_READ")
+	catalog.grantOnSchemaToDatabaseRole(sales, raw, "USAGE", "SALES_READ")
+	// An account role with the same name must not be mistaken for the database role
+	catalog.grantOnSchema(raw, "CREATE TABLE", "SALES_READ")
+
+	salesCfg := dbconfig.NewDatabaseConfig("TT_SALES")
+	salesCfg.Schemas = []dbconfig.SchemaConfig{dbconfig.NewSchemaConfig("RAW")}
+	salesCfg.DatabaseRoles = map[string]dbconfig.DatabaseRoleConfig{
+		"SALES_READ": {
+			Comment:               ptr("Read sales"),
+			GrantedToAccountRoles: []string{"TT_ANALYST"},
+			DatabasePrivileges:    []string{"USAGE"},
+			SchemaPrivileges:      map[string][]string{"RAW": {"USAGE"}},
+		},
+	}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	roleGrants := fetchSchemaGrantsToDatabaseRole(t, db, "TT_SALES", "RAW", "SALES_READ")
+	require.Len(t, roleGrants, 1)
+	require.True(t, roleGrants[0].IsDatabaseRole())
+	require.Equal(t, "SALES_READ", roleGrants[0].GranteeRoleName())
+	require.Equal(t, "TT_SALES.SALES_READ", roleGrants[0].Grantee)
+	require.True(t, hasPrivilege(roleGrants, "USAGE"))
+	require.False(t, hasPrivilege(roleGrants, "CREATE TABLE"))
+
+	accountGrants := fetchSchemaGrants(t, db, "TT_SALES", "RAW", "SALES_READ")
+	require.Len(t, accountGrants, 1)
+	require.False(t, accountGrants[0].IsDatabaseRole())
+
+	require.Equal(t, []string{"TT_ANALYST"}, fetchDatabaseRoleGrantees(t, db, "TT_SALES", "SALES_READ"))
+	requireDatabaseRolesMatchConfig(t, db, salesCfg)
+}
+
+// TestFakeSnowflakeAccessRoleHelpers exercises SHOW GRANTS TO ROLE and SHOW
+// GRANTS OF ROLE parsing with a generated access role hierarchy
+func TestFakeSnowflakeAccessRoleHelpers(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	sales := catalog.addDatabase(fakeDatabase{Name: "TT_SALES", RetentionTime: 1})
+	raw := catalog.addSchema(sales, fakeSchema{Name: "RAW", RetentionTime: 1})
+
+	access := dbconfig.AccessRolesConfig{Enabled: true, NameTemplate: "AR_{database}_{schema}_{level}", ParentRole: ptr("SYSADMIN")}
+	ro, rw, owner := access.RoleName("TT_SALES", "RAW", "RO"), access.RoleName("TT_SALES", "RAW", "RW"), access.RoleName("TT_SALES", "RAW", "OWNER")
+	require.Equal(t, "AR_TT_SALES_RAW_RO", ro)
+
+	catalog.grantOnDatabase(sales, "USAGE", ro)
+	for level, role := range map[string]string{"RO": ro, "RW": rw, "OWNER": owner} {
+		for _, privilege := range dbconfig.AccessRoleSchemaPrivileges[level] {
+			catalog.grantOnSchema(raw, privilege, role)
+		}
+		for objectType, privileges := range dbconfig.AccessRoleObjectPrivileges[level] {
+			for _, privilege := range privileges {
+				catalog.grantFutureInSchema(raw, strings.ReplaceAll(strings.TrimSuffix(objectType, "S"), " ", "_"), privilege, role)
+			}
+		}
+	}
+	catalog.grantRole(ro, rw)
+	catalog.grantRole(rw, owner)
+	catalog.grantRole(owner, "SYSADMIN")
+	catalog.grantRole(ro, "TT_ANALYST")
+
+	rawCfg := dbconfig.NewSchemaConfig("RAW")
+	rawCfg.AccessRoles = &dbconfig.SchemaAccessRoles{ROGrantedToRoles: []string{"TT_ANALYST"}}
+	salesCfg := dbconfig.NewDatabaseConfig("TT_SALES")
+	salesCfg.Schemas = []dbconfig.SchemaConfig{rawCfg}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	requireAccessRolesMatchConfig(t, db, access, salesCfg, rawCfg)
+
+	rwGrants := fetchGrantsToRole(t, db, rw)
+	require.True(t, hasGrantOn(rwGrants, "USAGE", "ROLE", ro), "RO is listed as USAGE on ROLE for RW")
+	require.False(t, hasGrantOn(rwGrants, "USAGE", "SCHEMA", "TT_SALES.RAW"), "RW holds schema USAGE only through RO")
+	require.ElementsMatch(t, []string{rw, "TT_ANALYST"}, fetchRoleGrantees(t, db, ro))
+	require.Empty(t, fetchRoleGrantees(t, db, "SYSADMIN"))
+}
+
+// TestFakeSnowflakeUnsupportedStatement verifies the fake rejects SQL it does
+// not understand instead of returning empty results
+func TestFakeSnowflakeUnsupportedStatement(t *testing.T) {
+	useFakeSnowflake(t)
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	_, err := db.Query("SELECT CURRENT_ROLE();")
+	require.ErrorContains(t, err, "unsupported statement")
+
+	_, err = db.Query("SHOW SCHEMAS IN DATABASE TT_MISSING;")
+	require.ErrorContains(t, err, "does not exist")
+}
+
+// TestFakeSnowflakeEndpoint verifies the Snowflake driver logs in with a
+// placeholder key and pings through the fake REST endpoint, the way the
+// provider does when a plan-only run configures it
+func TestFakeSnowflakeEndpoint(t *testing.T) {
+	endpoint := startFakeSnowflakeEndpoint(t)
+	privateKey, _ := placeholderPrivateKey(t)
+
+	port, err := strconv.Atoi(endpoint.Port())
+	require.NoError(t, err)
+
+	dsn, err := gosnowflake.DSN(&gosnowflake.Config{
+		Account:       "placeholder-account",
+		User:          "PLACEHOLDER",
+		Authenticator: gosnowflake.AuthTypeJwt,
+		PrivateKey:    privateKey,
+		Host:          endpoint.Hostname(),
+		Port:          port,
+		Protocol:      endpoint.Scheme,
+	})
+	require.NoError(t, err)
+
+	db, err := sql.Open("snowflake", dsn)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	require.NoError(t, db.Ping())
+
+	var one int
+	require.NoError(t, db.QueryRow("SELECT 1").Scan(&one))
+	require.Equal(t, 1, one)
+}
@@ -0,0 +1,229 @@
+// File: test/fidelity_test.go
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/drift"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/show"
+)
+
+// TestConfigurationFidelityHelpers verifies the fidelity assertions against the
+// fake driver, including schema retention inherited from the database and
+// transferred ownership
+func TestConfigurationFidelityHelpers(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	warehouse := catalog.addDatabase(fakeDatabase{Name: "TT_DW", Comment: "Warehouse", RetentionTime: 7, Owner: "TT_DW_ADMIN"})
+	catalog.addSchema(warehouse, fakeSchema{Name: "RAW", RetentionTime: 7, Owner: "TT_LOADER"})
+	catalog.addSchema(warehouse, fakeSchema{Name: "CURATED", RetentionTime: 14, IsManagedAccess: true})
+	catalog.addSchema(warehouse, fakeSchema{Name: "STAGING", RetentionTime: 1, IsTransient: true})
+	dev := catalog.addDatabase(fakeDatabase{Name: "TT_DEV", RetentionTime: 1, IsTransient: true})
+	catalog.addSchema(dev, fakeSchema{Name: "SANDBOX", RetentionTime: 1, IsTransient: true})
+
+	raw := dbconfig.NewSchemaConfig("RAW")
+	raw.OwnerRole = ptr("TT_LOADER")
+
+	curated := dbconfig.NewSchemaConfig("CURATED")
+	curated.IsManaged = true
+	curated.DataRetentionTimeInDays = ptr(14)
+
+	staging := dbconfig.NewSchemaConfig("STAGING")
+	staging.IsTransient = true
+
+	warehouseCfg := dbconfig.NewDatabaseConfig("TT_DW")
+	warehouseCfg.Comment = ptr("Warehouse")
+	warehouseCfg.DataRetentionTimeInDays = 7
+	warehouseCfg.OwnerRole = ptr("TT_DW_ADMIN")
+	warehouseCfg.Schemas = []dbconfig.SchemaConfig{raw, curated, staging}
+
+	devCfg := dbconfig.NewDatabaseConfig("TT_DEV")
+	devCfg.IsTransient = true
+	devCfg.Schemas = []dbconfig.SchemaConfig{dbconfig.NewSchemaConfig("SANDBOX")}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	for _, cfg := range []dbconfig.DatabaseConfig{warehouseCfg, devCfg} {
+		props := fetchDatabaseProps(t, db, cfg.Name)
+		requireDatabaseMatchesConfig(t, cfg, props)
+		for _, schema := range cfg.Schemas {
+			requireSchemaMatchesConfig(t, cfg, schema, fetchSchemaProps(t, db, cfg.Name, schema.Name))
+		}
+	}
+
+	require.True(t, fetchDatabaseProps(t, db, "TT_DEV").IsTransient)
+	require.Equal(t, "TT_DW_ADMIN", fetchSchemaProps(t, db, "TT_DW", "CURATED").Owner, "schemas default to the database owner")
+	require.Equal(t, 7, warehouseCfg.SchemaRetention(raw), "null schema retention inherits the database value")
+	require.Equal(t, 1, warehouseCfg.SchemaRetention(staging), "transient schemas cap inherited retention at one day")
+}
+
+// TestGrantFidelityHelpers verifies requireGrantsMatchConfig against the fake
+// driver: listed roles hold each privilege and unlisted roles hold none
+func TestGrantFidelityHelpers(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	app := catalog.addDatabase(fakeDatabase{Name: "TT_APP", RetentionTime: 1})
+	catalog.grantOnDatabase(app, "USAGE", "TT_READER")
+	raw := catalog.addSchema(app, fakeSchema{Name: "RAW", RetentionTime: 1})
+	catalog.grantOnSchema(raw, "USAGE", "TT_READER")
+	catalog.grantOnSchema(raw, "CREATE TABLE", "TT_WRITER")
+	catalog.grantFutureInSchema(raw, "TABLE", "SELECT", "TT_READER")
+
+	rawCfg := dbconfig.NewSchemaConfig("RAW")
+	rawCfg.Grants = &dbconfig.SchemaGrants{
+		UsageRoles:       []string{"TT_READER"},
+		CreateTableRoles: []string{"TT_WRITER"},
+	}
+	rawCfg.FutureGrants = map[string]map[string][]string{
+		"tables": {"select": {"TT_READER"}},
+	}
+
+	appCfg := dbconfig.NewDatabaseConfig("TT_APP")
+	appCfg.Grants = &dbconfig.DatabaseGrants{UsageRoles: []string{"TT_READER"}}
+	appCfg.Schemas = []dbconfig.SchemaConfig{rawCfg}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	requireGrantsMatchConfig(t, db, appCfg, dbconfig.AccessRolesConfig{})
+
+	// The drift detector reads the same grants: a schema config that drops
+	// CREATE TABLE and moves the future grant to views reports both sides
+	rawCfg.Grants = &dbconfig.SchemaGrants{UsageRoles: []string{"TT_READER"}}
+	rawCfg.FutureGrants = map[string]map[string][]string{"VIEWS": {"SELECT": {"TT_READER"}}}
+	appCfg.Schemas = []dbconfig.SchemaConfig{rawCfg}
+
+	report, err := drift.Detect(db, dbconfig.DatabaseConfigs{"app": appCfg}, drift.Options{})
+	require.NoError(t, err)
+	require.Equal(t, []drift.GrantDrift{{Object: "TT_APP.RAW", Privilege: "SELECT ON FUTURE VIEWS", Role: "TT_READER"}}, report.MissingGrants)
+	require.Equal(t, []drift.GrantDrift{
+		{Object: "TT_APP.RAW", Privilege: "CREATE TABLE", Role: "TT_WRITER"},
+		{Object: "TT_APP.RAW", Privilege: "SELECT ON FUTURE TABLES", Role: "TT_READER"},
+	}, report.ExtraGrants)
+}
+
+// TestParameterFidelityHelpers verifies requireParametersMatchConfig against
+// the fake driver: schemas report their own parameters at level SCHEMA and
+// inherit unset ones from the database, including log and trace levels
+func TestParameterFidelityHelpers(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	lake := catalog.addDatabase(fakeDatabase{Name: "TT_LAKE", RetentionTime: 1, Parameters: map[string]string{
+		"MAX_DATA_EXTENSION_TIME_IN_DAYS": "30",
+		"DEFAULT_DDL_COLLATION":           "en-ci",
+		"LOG_LEVEL":                       "WARN",
+		"TRACE_LEVEL":                     "ON_EVENT",
+		"EVENT_TABLE":                     "TT_OBS.TELEMETRY.EVENTS",
+	}})
+	catalog.addSchema(lake, fakeSchema{Name: "RAW", RetentionTime: 1, Parameters: map[string]string{
+		"REPLACE_INVALID_CHARACTERS":   "true",
+		"STORAGE_SERIALIZATION_POLICY": "COMPATIBLE",
+	}})
+	catalog.addSchema(lake, fakeSchema{Name: "CURATED", RetentionTime: 1, Parameters: map[string]string{
+		"MAX_DATA_EXTENSION_TIME_IN_DAYS": "7",
+		"LOG_LEVEL":                       "DEBUG",
+	}})
+
+	raw := dbconfig.NewSchemaConfig("RAW")
+	raw.ReplaceInvalidCharacters = ptr(true)
+	raw.StorageSerializationPolicy = ptr("compatible")
+
+	curated := dbconfig.NewSchemaConfig("CURATED")
+	curated.MaxDataExtensionTimeInDays = ptr(7)
+	curated.LogLevel = ptr("debug")
+
+	lakeCfg := dbconfig.NewDatabaseConfig("TT_LAKE")
+	lakeCfg.MaxDataExtensionTimeInDays = ptr(30)
+	lakeCfg.DefaultDDLCollation = ptr("en-ci")
+	lakeCfg.LogLevel = ptr("WARN")
+	lakeCfg.TraceLevel = ptr("on_event")
+	lakeCfg.EventTable = ptr("tt_obs.telemetry.events")
+	lakeCfg.Schemas = []dbconfig.SchemaConfig{raw, curated}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	requireParametersMatchConfig(t, db, lakeCfg)
+	requireParameter(t, fetchSchemaParameters(t, db, "TT_LAKE", "RAW"), "MAX_DATA_EXTENSION_TIME_IN_DAYS", "30", "DATABASE")
+	requireParameter(t, fetchSchemaParameters(t, db, "TT_LAKE", "CURATED"), "STORAGE_SERIALIZATION_POLICY", "OPTIMIZED", "")
+	requireParameter(t, fetchSchemaParameters(t, db, "TT_LAKE", "CURATED"), "LOG_LEVEL", "DEBUG", "SCHEMA")
+	requireParameter(t, fetchSchemaParameters(t, db, "TT_LAKE", "RAW"), "LOG_LEVEL", "WARN", "DATABASE")
+	require.Equal(t, map[string]string{"REPLACE_INVALID_CHARACTERS": "true", "STORAGE_SERIALIZATION_POLICY": "COMPATIBLE"}, raw.Values())
+}
+
+// TestTagFidelityHelpers verifies requireTagsMatchConfig against the fake
+// driver and that inherited database tags are not counted as schema tags
+func TestTagFidelityHelpers(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	finance := catalog.addDatabase(fakeDatabase{Name: "TT_FINANCE", RetentionTime: 1, Tags: map[string]string{
+		"TT_GOV.TAGS.COST_CENTER": "FIN-001",
+		"TT_GOV.TAGS.SENSITIVITY": "internal",
+	}})
+	catalog.addSchema(finance, fakeSchema{Name: "LEDGER", RetentionTime: 1, Tags: map[string]string{
+		"TT_GOV.TAGS.SENSITIVITY": "confidential",
+	}})
+	catalog.addSchema(finance, fakeSchema{Name: "REPORTS", RetentionTime: 1})
+
+	ledger := dbconfig.NewSchemaConfig("LEDGER")
+	ledger.Tags = map[string]string{"tt_gov.tags.sensitivity": "confidential"}
+
+	financeCfg := dbconfig.NewDatabaseConfig("TT_FINANCE")
+	financeCfg.Tags = map[string]string{
+		"TT_GOV.TAGS.COST_CENTER": "FIN-001",
+		"TT_GOV.TAGS.SENSITIVITY": "internal",
+	}
+	financeCfg.Schemas = []dbconfig.SchemaConfig{ledger, dbconfig.NewSchemaConfig("REPORTS")}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	requireTagsMatchConfig(t, db, financeCfg)
+
+	refs := fetchTagReferences(t, db, "TT_FINANCE", "TT_FINANCE.REPORTS", "SCHEMA")
+	require.Len(t, refs, 2, "REPORTS inherits both database tags")
+	require.Equal(t, "DATABASE", refs[0].Level)
+	require.Equal(t, "TT_GOV.TAGS.COST_CENTER", refs[0].TagFQN())
+
+	refs = fetchTagReferences(t, db, "TT_FINANCE", "TT_FINANCE.LEDGER", "SCHEMA")
+	require.Len(t, refs, 2)
+	require.Equal(t, TagReference{
+		TagDatabase: "TT_GOV", TagSchema: "TAGS", TagName: "SENSITIVITY", TagValue: "confidential",
+		Level: "SCHEMA", ObjectDatabase: "TT_FINANCE", ObjectName: "LEDGER", Domain: "SCHEMA",
+	}, refs[0])
+}
+
+// TestRenameFidelityHelpers verifies the rename assertions against the fake
+// driver: a renamed object keeps created_on, and a dropped one shows up in
+// the HISTORY listings with dropped_on set
+func TestRenameFidelityHelpers(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	createdOn := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	renamed := catalog.addDatabase(fakeDatabase{Name: "TT_FINANCE", RetentionTime: 1, CreatedOn: createdOn})
+	catalog.addSchema(renamed, fakeSchema{Name: "LANDING", RetentionTime: 1})
+	catalog.addSchema(renamed, fakeSchema{Name: "ARCHIVE", RetentionTime: 1, DroppedOn: createdOn.Add(time.Hour)})
+	catalog.addDatabase(fakeDatabase{Name: "TT_LEDGER", RetentionTime: 1, DroppedOn: createdOn.Add(time.Hour)})
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	// Captured before the rename, when the objects had their old names
+	before := show.DatabaseProps{Name: "TT_ACCOUNTING", CreatedOn: createdOn}
+	after := requireDatabaseRenamed(t, db, before, "TT_FINANCE")
+	require.Nil(t, after.DroppedOn)
+
+	schemaBefore := show.SchemaProps{Name: "STAGING", CreatedOn: createdOn}
+	requireSchemaRenamed(t, db, "TT_FINANCE", schemaBefore, "LANDING")
+
+	require.False(t, databaseExists(t, db, "TT_LEDGER"), "dropped databases are not listed by SHOW DATABASES")
+	history := fetchDatabaseHistory(t, db, "TT_LEDGER")
+	require.Len(t, history, 1)
+	require.NotNil(t, history[0].DroppedOn)
+	require.True(t, createdOn.Add(time.Hour).Equal(*history[0].DroppedOn))
+
+	require.False(t, schemaExists(t, db, "TT_FINANCE", "ARCHIVE"))
+	schemaHistory := fetchSchemaHistory(t, db, "TT_FINANCE", "%")
+	require.Len(t, schemaHistory, 2)
+	require.Nil(t, schemaHistory[0].DroppedOn, "LANDING still exists")
+	require.NotNil(t, schemaHistory[1].DroppedOn, "ARCHIVE was dropped")
+}
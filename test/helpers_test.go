@@ -8,32 +8,29 @@ import (
 	"encoding/pem"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/snowflakedb/gosnowflake"
 	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/drift"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/show"
 )
 
-type DatabaseProps struct {
-	Name                    string
-	Comment                 string
-	DataRetentionTimeInDays int
-	IsTransient             bool
-}
-
-type SchemaProps struct {
-	Name                    string
-	DatabaseName            string
-	Comment                 string
-	IsTransient             bool
-	IsManagedAccess         bool
-	DataRetentionTimeInDays int
-}
-
 func openSnowflake(t *testing.T) *sql.DB {
 	t.Helper()
 
+	// Use the in-memory fake driver for offline runs
+	if os.Getenv("SNOWFLAKE_TEST_DRIVER") == "fake" {
+		db, err := sql.Open(fakeDriverName, os.Getenv("SNOWFLAKE_FAKE_CATALOG"))
+		require.NoError(t, err)
+		require.NoError(t, db.Ping())
+		return db
+	}
+
 	orgName := mustEnv(t, "SNOWFLAKE_ORGANIZATION_NAME")
 	accountName := mustEnv(t, "SNOWFLAKE_ACCOUNT_NAME")
 	user := mustEnv(t, "SNOWFLAKE_USER")
@@ -114,120 +111,253 @@ func schemaExists(t *testing.T, db *sql.DB, databaseName, schemaName string) boo
 	return rows.Next()
 }
 
-func fetchDatabaseProps(t *testing.T, db *sql.DB, databaseName string) DatabaseProps {
+func fetchDatabaseProps(t *testing.T, db *sql.DB, databaseName string) show.DatabaseProps {
 	t.Helper()
 
+	var props []show.DatabaseProps
 	q := fmt.Sprintf("SHOW DATABASES LIKE '%s';", escapeLike(databaseName))
-	rows, err := db.Query(q)
-	require.NoError(t, err)
-	defer func() { _ = rows.Close() }()
+	require.NoError(t, show.Query(db, q, &props))
+	require.NotEmpty(t, props, "No database found matching %s", databaseName)
 
-	cols, err := rows.Columns()
-	require.NoError(t, err)
+	return props[0]
+}
 
-	nameIdx, commentIdx, retentionIdx, transientIdx := -1, -1, -1, -1
-	for i, col := range cols {
-		switch col {
-		case "name":
-			nameIdx = i
-		case "comment":
-			commentIdx = i
-		case "retention_time":
-			retentionIdx = i
-		case "is_transient":
-			transientIdx = i
-		}
-	}
-	require.NotEqual(t, -1, nameIdx, "name column not found")
+func fetchSchemaProps(t *testing.T, db *sql.DB, databaseName, schemaName string) show.SchemaProps {
+	t.Helper()
 
-	require.True(t, rows.Next(), "No database found matching %s", databaseName)
+	var props []show.SchemaProps
+	q := fmt.Sprintf("SHOW SCHEMAS LIKE '%s' IN DATABASE %s;", escapeLike(schemaName), databaseName)
+	require.NoError(t, show.Query(db, q, &props))
+	require.NotEmpty(t, props, "No schema found matching %s in database %s", schemaName, databaseName)
 
-	values := make([]interface{}, len(cols))
-	valuePtrs := make([]interface{}, len(cols))
-	for i := range values {
-		valuePtrs[i] = &values[i]
-	}
+	return props[0]
+}
 
-	err = rows.Scan(valuePtrs...)
-	require.NoError(t, err)
+// fetchDatabaseHistory returns the databases matching databaseName from SHOW
+// DATABASES HISTORY, including dropped ones that are still in Time Travel
+func fetchDatabaseHistory(t *testing.T, db *sql.DB, databaseName string) []show.DatabaseProps {
+	t.Helper()
 
-	props := DatabaseProps{
-		Name: getString(values[nameIdx]),
-	}
-	if commentIdx != -1 {
-		props.Comment = getString(values[commentIdx])
-	}
-	if retentionIdx != -1 {
-		props.DataRetentionTimeInDays = getInt(values[retentionIdx])
-	}
-	if transientIdx != -1 {
-		props.IsTransient = getString(values[transientIdx]) == "true"
-	}
+	var props []show.DatabaseProps
+	q := fmt.Sprintf("SHOW DATABASES HISTORY LIKE '%s';", escapeLike(databaseName))
+	require.NoError(t, show.Query(db, q, &props))
+	return props
+}
+
+// fetchSchemaHistory returns the schemas matching schemaName from SHOW
+// SCHEMAS HISTORY, including dropped ones that are still in Time Travel
+func fetchSchemaHistory(t *testing.T, db *sql.DB, databaseName, schemaName string) []show.SchemaProps {
+	t.Helper()
 
+	var props []show.SchemaProps
+	q := fmt.Sprintf("SHOW SCHEMAS HISTORY LIKE '%s' IN DATABASE %s;", escapeLike(schemaName), databaseName)
+	require.NoError(t, show.Query(db, q, &props))
 	return props
 }
 
-func fetchSchemaProps(t *testing.T, db *sql.DB, databaseName, schemaName string) SchemaProps {
+// requireDatabaseRenamed asserts the database captured in before now exists
+// as newName and is the same object: created_on is unchanged and no dropped
+// copy was left under either name, as a drop and create would leave one.
+func requireDatabaseRenamed(t *testing.T, db *sql.DB, before show.DatabaseProps, newName string) show.DatabaseProps {
 	t.Helper()
 
-	q := fmt.Sprintf("SHOW SCHEMAS LIKE '%s' IN DATABASE %s;", escapeLike(schemaName), databaseName)
-	rows, err := db.Query(q)
-	require.NoError(t, err)
-	defer func() { _ = rows.Close() }()
+	require.False(t, databaseExists(t, db, before.Name), "Expected database %s to no longer exist under its old name", before.Name)
+	after := fetchDatabaseProps(t, db, newName)
+	require.True(t, before.CreatedOn.Equal(after.CreatedOn),
+		"Expected database %s to keep created_on %s after the rename to %s, got %s", before.Name, before.CreatedOn, newName, after.CreatedOn)
+	for _, name := range []string{before.Name, newName} {
+		for _, p := range fetchDatabaseHistory(t, db, name) {
+			require.Nil(t, p.DroppedOn, "Expected no dropped database %s, found one dropped on %v", p.Name, p.DroppedOn)
+		}
+	}
+	return after
+}
 
-	cols, err := rows.Columns()
-	require.NoError(t, err)
+// requireSchemaRenamed is requireDatabaseRenamed for a schema in databaseName
+func requireSchemaRenamed(t *testing.T, db *sql.DB, databaseName string, before show.SchemaProps, newName string) show.SchemaProps {
+	t.Helper()
 
-	nameIdx, dbIdx, commentIdx, retentionIdx, transientIdx, optionsIdx := -1, -1, -1, -1, -1, -1
-	for i, col := range cols {
-		switch col {
-		case "name":
-			nameIdx = i
-		case "database_name":
-			dbIdx = i
-		case "comment":
-			commentIdx = i
-		case "retention_time":
-			retentionIdx = i
-		case "is_transient":
-			transientIdx = i
-		case "options":
-			optionsIdx = i
+	require.False(t, schemaExists(t, db, databaseName, before.Name), "Expected schema %s.%s to no longer exist under its old name", databaseName, before.Name)
+	after := fetchSchemaProps(t, db, databaseName, newName)
+	require.True(t, before.CreatedOn.Equal(after.CreatedOn),
+		"Expected schema %s to keep created_on %s after the rename to %s, got %s", before.Name, before.CreatedOn, newName, after.CreatedOn)
+	for _, name := range []string{before.Name, newName} {
+		for _, p := range fetchSchemaHistory(t, db, databaseName, name) {
+			require.Nil(t, p.DroppedOn, "Expected no dropped schema %s.%s, found one dropped on %v", databaseName, p.Name, p.DroppedOn)
 		}
 	}
-	require.NotEqual(t, -1, nameIdx, "name column not found")
+	return after
+}
+
+// ParameterInfo is a SHOW PARAMETERS row. Level names where the value was
+// set: the object itself (DATABASE or SCHEMA), a parent it inherits from, or
+// empty for the Snowflake default.
+type ParameterInfo struct {
+	Key         string `sf:"key"`
+	Value       string `sf:"value"`
+	Default     string `sf:"default"`
+	Level       string `sf:"level"`
+	Description string `sf:"description"`
+	Type        string `sf:"type"`
+}
+
+// Parameters maps an upper-case parameter key to its SHOW PARAMETERS row
+type Parameters map[string]ParameterInfo
+
+// IntValue parses a NUMBER parameter
+func (p ParameterInfo) IntValue() (int, error) {
+	return strconv.Atoi(p.Value)
+}
+
+// SetOn reports whether the parameter was set on an object of the given type
+// rather than inherited, e.g. SetOn("SCHEMA") for SHOW PARAMETERS IN SCHEMA
+func (p ParameterInfo) SetOn(objectType string) bool {
+	return strings.EqualFold(p.Level, objectType)
+}
+
+// fetchDatabaseParameters returns the parameters of a database, including
+// those it inherits from the account
+func fetchDatabaseParameters(t *testing.T, db *sql.DB, databaseName string) Parameters {
+	t.Helper()
+
+	return fetchParameters(t, db, fmt.Sprintf("SHOW PARAMETERS IN DATABASE %s;", databaseName))
+}
 
-	require.True(t, rows.Next(), "No schema found matching %s in database %s", schemaName, databaseName)
+// fetchSchemaParameters returns the parameters of a schema, including those
+// it inherits from the database or the account
+func fetchSchemaParameters(t *testing.T, db *sql.DB, databaseName, schemaName string) Parameters {
+	t.Helper()
+
+	return fetchParameters(t, db, fmt.Sprintf("SHOW PARAMETERS IN SCHEMA %s.%s;", databaseName, schemaName))
+}
+
+func fetchParameters(t *testing.T, db *sql.DB, q string) Parameters {
+	t.Helper()
+
+	var rows []ParameterInfo
+	require.NoError(t, show.Query(db, q, &rows))
 
-	values := make([]interface{}, len(cols))
-	valuePtrs := make([]interface{}, len(cols))
-	for i := range values {
-		valuePtrs[i] = &values[i]
+	params := make(Parameters, len(rows))
+	for _, p := range rows {
+		params[strings.ToUpper(p.Key)] = p
 	}
+	return params
+}
 
-	err = rows.Scan(valuePtrs...)
-	require.NoError(t, err)
+// requireParametersMatchConfig asserts the parameters set in database_configs
+// on the database and each schema. A schema that leaves a parameter unset is
+// expected to inherit the database value at level DATABASE.
+func requireParametersMatchConfig(t *testing.T, db *sql.DB, cfg dbconfig.DatabaseConfig) {
+	t.Helper()
 
-	props := SchemaProps{
-		Name: getString(values[nameIdx]),
+	dbValues := cfg.ObjectParameters.Values()
+	dbParams := fetchDatabaseParameters(t, db, cfg.Name)
+	for key, value := range dbValues {
+		requireParameter(t, dbParams, key, value, "DATABASE")
 	}
-	if dbIdx != -1 {
-		props.DatabaseName = getString(values[dbIdx])
+	if cfg.EventTable != nil {
+		requireParameter(t, dbParams, "EVENT_TABLE", strings.ToUpper(*cfg.EventTable), "DATABASE")
 	}
-	if commentIdx != -1 {
-		props.Comment = getString(values[commentIdx])
+
+	for _, schema := range cfg.AllSchemas() {
+		schemaValues := schema.ObjectParameters.Values()
+		schemaParams := fetchSchemaParameters(t, db, cfg.Name, schema.Name)
+		for key, value := range schemaValues {
+			requireParameter(t, schemaParams, key, value, "SCHEMA")
+		}
+		for key, value := range dbValues {
+			if _, ok := schemaValues[key]; !ok {
+				requireParameter(t, schemaParams, key, value, "DATABASE")
+			}
+		}
 	}
-	if retentionIdx != -1 {
-		props.DataRetentionTimeInDays = getInt(values[retentionIdx])
+}
+
+// TagReference is a row of the INFORMATION_SCHEMA.TAG_REFERENCES table
+// function. Level is the domain the tag was set on, so a schema reports the
+// database tags it inherits at level DATABASE.
+type TagReference struct {
+	TagDatabase    string `sf:"tag_database"`
+	TagSchema      string `sf:"tag_schema"`
+	TagName        string `sf:"tag_name"`
+	TagValue       string `sf:"tag_value"`
+	Level          string `sf:"level"`
+	ObjectDatabase string `sf:"object_database"`
+	ObjectName     string `sf:"object_name"`
+	Domain         string `sf:"domain"`
+}
+
+// TagFQN returns the fully qualified tag name, as used in the tags maps
+func (r TagReference) TagFQN() string {
+	return r.TagDatabase + "." + r.TagSchema + "." + r.TagName
+}
+
+// fetchTagReferences runs TAG_REFERENCES for an object in a domain such as
+// DATABASE or SCHEMA. objectName is qualified the way Snowflake expects,
+// e.g. DB.SCHEMA for a schema.
+func fetchTagReferences(t *testing.T, db *sql.DB, databaseName, objectName, domain string) []TagReference {
+	t.Helper()
+
+	var refs []TagReference
+	q := fmt.Sprintf("SELECT * FROM TABLE(%s.INFORMATION_SCHEMA.TAG_REFERENCES('%s', '%s'));", databaseName, objectName, domain)
+	require.NoError(t, show.Query(db, q, &refs))
+	return refs
+}
+
+// fetchDatabaseTags returns the tags set on a database, keyed by fully
+// qualified tag name
+func fetchDatabaseTags(t *testing.T, db *sql.DB, databaseName string) map[string]string {
+	t.Helper()
+
+	return directTags(fetchTagReferences(t, db, databaseName, databaseName, "DATABASE"), "DATABASE")
+}
+
+// fetchSchemaTags returns the tags set on a schema itself, keyed by fully
+// qualified tag name. Tags inherited from the database are left out.
+func fetchSchemaTags(t *testing.T, db *sql.DB, databaseName, schemaName string) map[string]string {
+	t.Helper()
+
+	return directTags(fetchTagReferences(t, db, databaseName, databaseName+"."+schemaName, "SCHEMA"), "SCHEMA")
+}
+
+func directTags(refs []TagReference, level string) map[string]string {
+	tags := map[string]string{}
+	for _, r := range refs {
+		if strings.EqualFold(r.Level, level) {
+			tags[strings.ToUpper(r.TagFQN())] = r.TagValue
+		}
 	}
-	if transientIdx != -1 {
-		props.IsTransient = getString(values[transientIdx]) == "true"
+	return tags
+}
+
+// requireTagsMatchConfig asserts the database and every schema carry exactly
+// the tags listed in their tags maps
+func requireTagsMatchConfig(t *testing.T, db *sql.DB, cfg dbconfig.DatabaseConfig) {
+	t.Helper()
+
+	require.Equal(t, upperKeys(cfg.Tags), fetchDatabaseTags(t, db, cfg.Name), "tags on database %s", cfg.Name)
+	for _, schema := range cfg.AllSchemas() {
+		require.Equal(t, upperKeys(schema.Tags), fetchSchemaTags(t, db, cfg.Name, schema.Name), "tags on schema %s.%s", cfg.Name, schema.Name)
 	}
-	if optionsIdx != -1 {
-		props.IsManagedAccess = strings.Contains(getString(values[optionsIdx]), "MANAGED ACCESS")
+}
+
+func upperKeys(m map[string]string) map[string]string {
+	upper := make(map[string]string, len(m))
+	for k, v := range m {
+		upper[strings.ToUpper(k)] = v
 	}
+	return upper
+}
 
-	return props
+// requireParameter asserts a parameter's value and the level it was set at.
+// An empty level expects the Snowflake default.
+func requireParameter(t *testing.T, params Parameters, key, value, level string) {
+	t.Helper()
+
+	p, ok := params[strings.ToUpper(key)]
+	require.True(t, ok, "Parameter %s not reported", key)
+	require.Equal(t, value, p.Value, "value of parameter %s", key)
+	require.True(t, strings.EqualFold(level, p.Level), "Expected parameter %s at level %q, got %q", key, level, p.Level)
 }
 
 func getString(v interface{}) string {
@@ -243,6 +373,24 @@ func getString(v interface{}) string {
 	return fmt.Sprintf("%v", v)
 }
 
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// findByName returns the item whose name matches, ignoring case
+func findByName[T any](items []T, name string, nameOf func(T) string) (T, bool) {
+	for _, item := range items {
+		if strings.EqualFold(nameOf(item), name) {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
 func getInt(v interface{}) int {
 	if v == nil {
 		return 0
@@ -266,137 +414,340 @@ func getInt(v interface{}) int {
 	return 0
 }
 
-// GrantInfo represents a grant privilege record
-type GrantInfo struct {
-	Privilege string
-	GrantedOn string
-	Name      string
-	GrantedTo string
-	Grantee   string
-}
-
 // fetchDatabaseGrants retrieves grants on a database for a specific role
-func fetchDatabaseGrants(t *testing.T, db *sql.DB, databaseName, roleName string) []GrantInfo {
+func fetchDatabaseGrants(t *testing.T, db *sql.DB, databaseName, roleName string) []show.GrantInfo {
 	t.Helper()
 
 	q := fmt.Sprintf("SHOW GRANTS ON DATABASE %s;", databaseName)
-	rows, err := db.Query(q)
-	require.NoError(t, err)
-	defer func() { _ = rows.Close() }()
+	return fetchGrants(t, db, q, roleName)
+}
 
-	cols, err := rows.Columns()
-	require.NoError(t, err)
+// fetchSchemaGrants retrieves grants on a schema for a specific role
+func fetchSchemaGrants(t *testing.T, db *sql.DB, databaseName, schemaName, roleName string) []show.GrantInfo {
+	t.Helper()
+
+	q := fmt.Sprintf("SHOW GRANTS ON SCHEMA %s.%s;", databaseName, schemaName)
+	return fetchGrants(t, db, q, roleName)
+}
+
+// fetchGrants runs a SHOW GRANTS statement and keeps the grants to roleName
+func fetchGrants(t *testing.T, db *sql.DB, q, roleName string) []show.GrantInfo {
+	t.Helper()
+
+	var all []show.GrantInfo
+	require.NoError(t, show.Query(db, q, &all))
 
-	privIdx, grantedOnIdx, nameIdx, grantedToIdx, granteeIdx := -1, -1, -1, -1, -1
-	for i, col := range cols {
-		switch col {
-		case "privilege":
-			privIdx = i
-		case "granted_on":
-			grantedOnIdx = i
-		case "name":
-			nameIdx = i
-		case "granted_to":
-			grantedToIdx = i
-		case "grantee_name":
-			granteeIdx = i
+	var grants []show.GrantInfo
+	for _, g := range all {
+		// Filter by role name
+		if strings.EqualFold(g.Grantee, roleName) {
+			grants = append(grants, g)
 		}
 	}
 
-	var grants []GrantInfo
-	for rows.Next() {
-		values := make([]interface{}, len(cols))
-		valuePtrs := make([]interface{}, len(cols))
-		for i := range values {
-			valuePtrs[i] = &values[i]
+	return grants
+}
+
+// fetchSchemaFutureGrants retrieves future grants in a schema for a specific role
+func fetchSchemaFutureGrants(t *testing.T, db *sql.DB, databaseName, schemaName, roleName string) []show.FutureGrantInfo {
+	t.Helper()
+
+	var all []show.FutureGrantInfo
+	q := fmt.Sprintf("SHOW FUTURE GRANTS IN SCHEMA %s.%s;", databaseName, schemaName)
+	require.NoError(t, show.Query(db, q, &all))
+
+	var grants []show.FutureGrantInfo
+	for _, g := range all {
+		// Filter by role name
+		if strings.EqualFold(g.Grantee, roleName) {
+			grants = append(grants, g)
 		}
+	}
 
-		err = rows.Scan(valuePtrs...)
-		require.NoError(t, err)
+	return grants
+}
+
+// hasFutureGrant checks if a list of future grants contains a privilege on a
+// plural object type such as TABLES
+func hasFutureGrant(grants []show.FutureGrantInfo, objectType, privilege string) bool {
+	for _, g := range grants {
+		if strings.EqualFold(g.ObjectTypePlural(), objectType) && strings.EqualFold(g.Privilege, privilege) {
+			return true
+		}
+	}
+	return false
+}
+
+// ObjectInfo represents a SHOW OBJECTS record. Kind is the singular object
+// type, e.g. TABLE or VIEW.
+type ObjectInfo struct {
+	Name         string `sf:"name"`
+	DatabaseName string `sf:"database_name"`
+	SchemaName   string `sf:"schema_name"`
+	Kind         string `sf:"kind"`
+	Owner        string `sf:"owner"`
+}
+
+// fetchSchemaObjects lists the tables and views in a schema
+func fetchSchemaObjects(t *testing.T, db *sql.DB, databaseName, schemaName string) []ObjectInfo {
+	t.Helper()
+
+	var objects []ObjectInfo
+	q := fmt.Sprintf("SHOW OBJECTS IN SCHEMA %s.%s;", databaseName, schemaName)
+	require.NoError(t, show.Query(db, q, &objects))
+	return objects
+}
 
-		grantee := ""
-		if granteeIdx != -1 {
-			grantee = getString(values[granteeIdx])
+// objectsMissingPrivilege enumerates the objects of a plural object type in a
+// schema with SHOW OBJECTS and returns the fully qualified names of those on
+// which role does not hold privilege
+func objectsMissingPrivilege(db *sql.DB, databaseName, schemaName, objectType, privilege, role string) ([]string, error) {
+	var objects []ObjectInfo
+	q := fmt.Sprintf("SHOW OBJECTS IN SCHEMA %s.%s;", databaseName, schemaName)
+	if err := show.Query(db, q, &objects); err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, obj := range objects {
+		if !strings.EqualFold(show.PluralObjectType(obj.Kind), objectType) {
+			continue
 		}
+		name := fmt.Sprintf("%s.%s.%s", databaseName, schemaName, obj.Name)
+		kind := strings.ReplaceAll(strings.ToUpper(obj.Kind), "_", " ")
 
-		// Filter by role name
-		if strings.EqualFold(grantee, roleName) {
-			grant := GrantInfo{
-				Privilege: getString(values[privIdx]),
-				GrantedOn: getString(values[grantedOnIdx]),
-				Name:      getString(values[nameIdx]),
-				GrantedTo: getString(values[grantedToIdx]),
-				Grantee:   grantee,
+		var grants []show.GrantInfo
+		if err := show.Query(db, fmt.Sprintf("SHOW GRANTS ON %s %s;", kind, name), &grants); err != nil {
+			return nil, err
+		}
+		held := false
+		for _, g := range grants {
+			held = held || (strings.EqualFold(g.Grantee, role) && strings.EqualFold(g.Privilege, privilege))
+		}
+		if !held {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
+
+// requireAllObjectsGranted asserts that every existing object covered by the
+// schema's all_objects_grants carries the configured privilege for each role
+func requireAllObjectsGranted(t *testing.T, db *sql.DB, dbCfg dbconfig.DatabaseConfig, cfg dbconfig.SchemaConfig) {
+	t.Helper()
+
+	for objectType, privileges := range cfg.AllObjectsGrants {
+		for privilege, roles := range privileges {
+			for _, role := range roles {
+				missing, err := objectsMissingPrivilege(db, dbCfg.Name, cfg.Name, objectType, privilege, role)
+				require.NoError(t, err)
+				require.Empty(t, missing, "Expected %s on all %s in %s.%s for role %s",
+					strings.ToUpper(privilege), strings.ToUpper(objectType), dbCfg.Name, cfg.Name, role)
 			}
-			grants = append(grants, grant)
 		}
 	}
+}
+
+// fetchSchemaGrantsToDatabaseRole retrieves grants on a schema to a database
+// role of the schema's database
+func fetchSchemaGrantsToDatabaseRole(t *testing.T, db *sql.DB, databaseName, schemaName, databaseRoleName string) []show.GrantInfo {
+	t.Helper()
+
+	var all []show.GrantInfo
+	q := fmt.Sprintf("SHOW GRANTS ON SCHEMA %s.%s;", databaseName, schemaName)
+	require.NoError(t, show.Query(db, q, &all))
 
+	var grants []show.GrantInfo
+	for _, g := range all {
+		if g.IsDatabaseRole() && strings.EqualFold(g.GranteeRoleName(), databaseRoleName) {
+			grants = append(grants, g)
+		}
+	}
 	return grants
 }
 
-// fetchSchemaGrants retrieves grants on a schema for a specific role
-func fetchSchemaGrants(t *testing.T, db *sql.DB, databaseName, schemaName, roleName string) []GrantInfo {
+// fetchDatabaseGrantsToDatabaseRole retrieves grants on a database to one of
+// its database roles
+func fetchDatabaseGrantsToDatabaseRole(t *testing.T, db *sql.DB, databaseName, databaseRoleName string) []show.GrantInfo {
 	t.Helper()
 
-	q := fmt.Sprintf("SHOW GRANTS ON SCHEMA %s.%s;", databaseName, schemaName)
-	rows, err := db.Query(q)
-	require.NoError(t, err)
-	defer func() { _ = rows.Close() }()
+	var all []show.GrantInfo
+	q := fmt.Sprintf("SHOW GRANTS ON DATABASE %s;", databaseName)
+	require.NoError(t, show.Query(db, q, &all))
 
-	cols, err := rows.Columns()
-	require.NoError(t, err)
+	var grants []show.GrantInfo
+	for _, g := range all {
+		if g.IsDatabaseRole() && strings.EqualFold(g.GranteeRoleName(), databaseRoleName) {
+			grants = append(grants, g)
+		}
+	}
+	return grants
+}
+
+// DatabaseRoleInfo represents a SHOW DATABASE ROLES record
+type DatabaseRoleInfo struct {
+	Name    string `sf:"name"`
+	Comment string `sf:"comment"`
+	Owner   string `sf:"owner"`
+}
+
+// fetchDatabaseRoles lists the database roles of a database
+func fetchDatabaseRoles(t *testing.T, db *sql.DB, databaseName string) []DatabaseRoleInfo {
+	t.Helper()
+
+	var roles []DatabaseRoleInfo
+	require.NoError(t, show.Query(db, fmt.Sprintf("SHOW DATABASE ROLES IN DATABASE %s;", databaseName), &roles))
+	return roles
+}
+
+// RoleGrant represents a SHOW GRANTS OF ROLE or SHOW GRANTS OF DATABASE ROLE
+// record: a role the role has been granted to
+type RoleGrant struct {
+	Role      string `sf:"role"`
+	GrantedTo string `sf:"granted_to"`
+	Grantee   string `sf:"grantee_name"`
+}
+
+// fetchDatabaseRoleGrantees returns the account roles a database role is
+// granted to
+func fetchDatabaseRoleGrantees(t *testing.T, db *sql.DB, databaseName, databaseRoleName string) []string {
+	t.Helper()
+
+	q := fmt.Sprintf("SHOW GRANTS OF DATABASE ROLE %s.%s;", databaseName, databaseRoleName)
+	return fetchGrantees(t, db, q)
+}
+
+// fetchRoleGrantees returns the roles and users an account role is granted
+// to, from SHOW GRANTS OF ROLE
+func fetchRoleGrantees(t *testing.T, db *sql.DB, roleName string) []string {
+	t.Helper()
+
+	return fetchGrantees(t, db, fmt.Sprintf("SHOW GRANTS OF ROLE %s;", roleName))
+}
+
+// fetchGrantees runs a SHOW GRANTS OF statement and keeps the account roles
+func fetchGrantees(t *testing.T, db *sql.DB, q string) []string {
+	t.Helper()
+
+	var all []RoleGrant
+	require.NoError(t, show.Query(db, q, &all))
+
+	var roles []string
+	for _, g := range all {
+		if strings.EqualFold(g.GrantedTo, "ROLE") {
+			roles = append(roles, g.Grantee)
+		}
+	}
+	return roles
+}
+
+// fetchGrantsToRole returns every privilege held by an account role, from
+// SHOW GRANTS TO ROLE. Roles granted to it appear as USAGE on a ROLE.
+func fetchGrantsToRole(t *testing.T, db *sql.DB, roleName string) []show.GrantInfo {
+	t.Helper()
+
+	var grants []show.GrantInfo
+	require.NoError(t, show.Query(db, fmt.Sprintf("SHOW GRANTS TO ROLE %s;", roleName), &grants))
+	return grants
+}
 
-	privIdx, grantedOnIdx, nameIdx, grantedToIdx, granteeIdx := -1, -1, -1, -1, -1
-	for i, col := range cols {
-		switch col {
-		case "privilege":
-			privIdx = i
-		case "granted_on":
-			grantedOnIdx = i
-		case "name":
-			nameIdx = i
-		case "granted_to":
-			grantedToIdx = i
-		case "grantee_name":
-			granteeIdx = i
+// hasGrantOn checks if a list of grants contains a privilege on a specific
+// object type and name, e.g. USAGE on SCHEMA DB.RAW
+func hasGrantOn(grants []show.GrantInfo, privilege, grantedOn, name string) bool {
+	for _, g := range grants {
+		if strings.EqualFold(g.Privilege, privilege) && strings.EqualFold(g.GrantedOn, grantedOn) && strings.EqualFold(g.Name, name) {
+			return true
 		}
 	}
+	return false
+}
 
-	var grants []GrantInfo
-	for rows.Next() {
-		values := make([]interface{}, len(cols))
-		valuePtrs := make([]interface{}, len(cols))
-		for i := range values {
-			valuePtrs[i] = &values[i]
+// requireAccessRolesMatchConfig asserts the generated RO, RW and OWNER roles
+// of a schema: each level holds its privilege bundle, future grants cover its
+// object privileges, RO is granted to RW, RW to OWNER, OWNER to the parent
+// role, and each level is granted to the roles listed on the schema
+func requireAccessRolesMatchConfig(t *testing.T, db *sql.DB, access dbconfig.AccessRolesConfig, dbCfg dbconfig.DatabaseConfig, cfg dbconfig.SchemaConfig) {
+	t.Helper()
+
+	object := dbCfg.Name + "." + cfg.Name
+	for _, level := range dbconfig.AccessRoleLevels {
+		role := access.RoleName(dbCfg.Name, cfg.Name, level)
+		grants := fetchGrantsToRole(t, db, role)
+
+		if level == "RO" {
+			require.True(t, hasGrantOn(grants, "USAGE", "DATABASE", dbCfg.Name), "Expected USAGE on database %s for %s", dbCfg.Name, role)
+		}
+		for _, privilege := range dbconfig.AccessRoleSchemaPrivileges[level] {
+			require.True(t, hasGrantOn(grants, privilege, "SCHEMA", object), "Expected %s on schema %s for %s", privilege, object, role)
 		}
 
-		err = rows.Scan(valuePtrs...)
-		require.NoError(t, err)
+		future := fetchSchemaFutureGrants(t, db, dbCfg.Name, cfg.Name, role)
+		for objectType, privileges := range dbconfig.AccessRoleObjectPrivileges[level] {
+			for _, privilege := range privileges {
+				require.True(t, hasFutureGrant(future, objectType, privilege), "Expected %s on future %s in %s for %s", privilege, objectType, object, role)
+			}
+		}
 
-		grantee := ""
-		if granteeIdx != -1 {
-			grantee = getString(values[granteeIdx])
+		grantees := upperAll(fetchRoleGrantees(t, db, role))
+		if parent := access.ParentOf(dbCfg.Name, cfg.Name, level); parent != "" {
+			require.Contains(t, grantees, strings.ToUpper(parent), "Expected %s to be granted to %s", role, parent)
+		}
+		for _, member := range cfg.AccessRoles.Members(level) {
+			require.Contains(t, grantees, strings.ToUpper(member), "Expected %s to be granted to %s", role, member)
 		}
+	}
+}
 
-		// Filter by role name
-		if strings.EqualFold(grantee, roleName) {
-			grant := GrantInfo{
-				Privilege: getString(values[privIdx]),
-				GrantedOn: getString(values[grantedOnIdx]),
-				Name:      getString(values[nameIdx]),
-				GrantedTo: getString(values[grantedToIdx]),
-				Grantee:   grantee,
+// requireDatabaseRolesMatchConfig asserts that every configured database role
+// exists with its comment, holds its database and schema privileges and is
+// granted to exactly the listed account roles
+func requireDatabaseRolesMatchConfig(t *testing.T, db *sql.DB, cfg dbconfig.DatabaseConfig) {
+	t.Helper()
+
+	live := fetchDatabaseRoles(t, db, cfg.Name)
+	for _, name := range sortedRoleNames(cfg.DatabaseRoles) {
+		role := cfg.DatabaseRoles[name]
+		object := cfg.Name + "." + name
+
+		props, ok := findByName(live, name, func(r DatabaseRoleInfo) string { return r.Name })
+		require.True(t, ok, "Expected database role %s", object)
+		require.Equal(t, derefString(role.Comment), props.Comment, "comment of database role %s", object)
+
+		dbGrants := fetchDatabaseGrantsToDatabaseRole(t, db, cfg.Name, name)
+		for _, privilege := range role.DatabasePrivileges {
+			require.True(t, hasPrivilege(dbGrants, privilege), "Expected %s on database %s for database role %s", privilege, cfg.Name, object)
+		}
+		for schemaName, privileges := range role.SchemaPrivileges {
+			schemaGrants := fetchSchemaGrantsToDatabaseRole(t, db, cfg.Name, schemaName, name)
+			for _, privilege := range privileges {
+				require.True(t, hasPrivilege(schemaGrants, privilege), "Expected %s on schema %s.%s for database role %s", privilege, cfg.Name, schemaName, object)
 			}
-			grants = append(grants, grant)
 		}
+
+		require.ElementsMatch(t, upperAll(role.GrantedToAccountRoles), upperAll(fetchDatabaseRoleGrantees(t, db, cfg.Name, name)),
+			"account roles granted database role %s", object)
 	}
+}
 
-	return grants
+func sortedRoleNames(roles map[string]dbconfig.DatabaseRoleConfig) []string {
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func upperAll(list []string) []string {
+	out := make([]string, len(list))
+	for i, s := range list {
+		out[i] = strings.ToUpper(s)
+	}
+	return out
 }
 
 // hasPrivilege checks if a list of grants contains a specific privilege
-func hasPrivilege(grants []GrantInfo, privilege string) bool {
+func hasPrivilege(grants []show.GrantInfo, privilege string) bool {
 	for _, g := range grants {
 		if strings.EqualFold(g.Privilege, privilege) {
 			return true
@@ -404,3 +755,104 @@ func hasPrivilege(grants []GrantInfo, privilege string) bool {
 	}
 	return false
 }
+
+// requireDatabaseMatchesConfig asserts every database attribute from
+// variables.tf against the properties fetched from Snowflake
+func requireDatabaseMatchesConfig(t *testing.T, cfg dbconfig.DatabaseConfig, props show.DatabaseProps) {
+	t.Helper()
+
+	require.Equal(t, cfg.Name, props.Name, "database name")
+	require.Equal(t, derefString(cfg.Comment), props.Comment, "comment of database %s", cfg.Name)
+	require.Equal(t, cfg.DataRetentionTimeInDays, props.DataRetentionTimeInDays, "data_retention_time_in_days of database %s", cfg.Name)
+	require.Equal(t, cfg.IsTransient, props.IsTransient, "is_transient of database %s", cfg.Name)
+	if cfg.OwnerRole != nil {
+		require.Equal(t, *cfg.OwnerRole, props.Owner, "owner_role of database %s", cfg.Name)
+	}
+}
+
+// requireSchemaMatchesConfig asserts every schema attribute from variables.tf
+// against the properties fetched from Snowflake. A null schema retention is
+// expected to inherit the database value, and schemas in a transient database
+// are always transient.
+func requireSchemaMatchesConfig(t *testing.T, dbCfg dbconfig.DatabaseConfig, cfg dbconfig.SchemaConfig, props show.SchemaProps) {
+	t.Helper()
+
+	object := dbCfg.Name + "." + cfg.Name
+	require.Equal(t, cfg.Name, props.Name, "schema name")
+	require.Equal(t, dbCfg.Name, props.DatabaseName, "database of schema %s", object)
+	require.Equal(t, derefString(cfg.Comment), props.Comment, "comment of schema %s", object)
+	require.Equal(t, dbCfg.SchemaRetention(cfg), props.DataRetentionTimeInDays, "data_retention_time_in_days of schema %s", object)
+	require.Equal(t, cfg.IsTransient || dbCfg.IsTransient, props.IsTransient, "is_transient of schema %s", object)
+	require.Equal(t, cfg.IsManaged, props.IsManagedAccess, "is_managed of schema %s", object)
+	if cfg.OwnerRole != nil {
+		require.Equal(t, *cfg.OwnerRole, props.Owner, "owner_role of schema %s", object)
+	}
+}
+
+// createRoles creates throwaway account roles for grant tests. The connected
+// role needs the CREATE ROLE privilege.
+func createRoles(t *testing.T, db *sql.DB, roles ...string) {
+	t.Helper()
+
+	for _, role := range roles {
+		_, err := db.Exec(fmt.Sprintf("CREATE ROLE IF NOT EXISTS %s;", role))
+		require.NoError(t, err, "Failed to create role %s", role)
+	}
+}
+
+// grantRolesToCurrentRole grants roles to the connected role so it keeps
+// control of objects whose ownership the module moves to them
+func grantRolesToCurrentRole(t *testing.T, db *sql.DB, roles ...string) {
+	t.Helper()
+
+	var current string
+	require.NoError(t, db.QueryRow("SELECT CURRENT_ROLE();").Scan(&current), "Failed to read the current role")
+	for _, role := range roles {
+		_, err := db.Exec(fmt.Sprintf("GRANT ROLE %s TO ROLE %s;", role, current))
+		require.NoError(t, err, "Failed to grant role %s to %s", role, current)
+	}
+}
+
+// dropRoles drops roles created by createRoles, ignoring errors so it can run
+// from a defer after a failed test
+func dropRoles(t *testing.T, db *sql.DB, roles ...string) {
+	t.Helper()
+
+	for _, role := range roles {
+		_, _ = db.Exec(fmt.Sprintf("DROP ROLE IF EXISTS %s;", role))
+	}
+}
+
+// requireGrantsMatchConfig asserts that every configured privilege on the
+// database and its schemas, including schema future grants and grants to
+// database roles, is granted to exactly the listed roles: each listed role
+// holds it and no other role does. The grants of the generated access roles
+// are expected when access enables them. OWNERSHIP is not considered.
+func requireGrantsMatchConfig(t *testing.T, db *sql.DB, cfg dbconfig.DatabaseConfig, access dbconfig.AccessRolesConfig) {
+	t.Helper()
+
+	dbPrivileges := dbconfig.MergePrivileges(cfg.Privileges(), access.DatabasePrivileges(cfg))
+	for privilege, roles := range dbPrivileges {
+		for _, role := range roles {
+			require.True(t, hasPrivilege(fetchDatabaseGrants(t, db, cfg.Name, role), privilege),
+				"Expected %s on database %s for role %s", privilege, cfg.Name, role)
+		}
+	}
+
+	for _, schema := range cfg.AllSchemas() {
+		object := cfg.Name + "." + schema.Name
+		schemaPrivileges := dbconfig.MergePrivileges(schema.Privileges(), access.SchemaPrivileges(cfg.Name, schema))
+		for privilege, roles := range schemaPrivileges {
+			for _, role := range roles {
+				require.True(t, hasPrivilege(fetchSchemaGrants(t, db, cfg.Name, schema.Name, role), privilege),
+					"Expected %s on schema %s for role %s", privilege, object, role)
+			}
+		}
+	}
+
+	// The drift detector compares the same grants both ways
+	report, err := drift.Detect(db, dbconfig.DatabaseConfigs{cfg.Name: cfg}, drift.Options{AccessRoles: access})
+	require.NoError(t, err)
+	require.Empty(t, report.MissingGrants, "Missing grants on %s:\n%s", cfg.Name, report)
+	require.Empty(t, report.ExtraGrants, "Unexpected grants on %s:\n%s", cfg.Name, report)
+}
@@ -0,0 +1,46 @@
+// File: test/idempotency_test.go
+package test
+
+import (
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDescribePlanChanges verifies the report requireIdempotent prints for a
+// plan that is not empty
+func TestDescribePlanChanges(t *testing.T) {
+	plan := &tfjson.Plan{ResourceChanges: []*tfjson.ResourceChange{
+		{
+			Address: `snowflake_schema.this["app.RAW"]`,
+			Change: &tfjson.Change{
+				Actions: tfjson.Actions{tfjson.ActionUpdate},
+				Before: map[string]interface{}{
+					"name": "RAW", "comment": "", "data_retention_time_in_days": float64(1), "is_transient": false,
+				},
+				After: map[string]interface{}{
+					"name": "RAW", "comment": nil, "data_retention_time_in_days": float64(7), "is_transient": false,
+				},
+				AfterUnknown: map[string]interface{}{"fully_qualified_name": true},
+			},
+		},
+		{
+			Address: `snowflake_database.this["app"]`,
+			Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}},
+		},
+		{
+			Address: `snowflake_grant_privileges_to_account_role.database_usage["app/TT_READER"]`,
+			Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate}},
+		},
+	}}
+
+	require.Equal(t, `snowflake_grant_privileges_to_account_role.database_usage["app/TT_READER"] (delete, create)
+snowflake_schema.this["app.RAW"] (update)
+  comment: "" -> null
+  data_retention_time_in_days: 1 -> 7
+  fully_qualified_name: null -> (known after apply)
+`, describePlanChanges(plan))
+
+	require.Empty(t, describePlanChanges(&tfjson.Plan{}))
+}
@@ -3,7 +3,6 @@ package test
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +10,7 @@ import (
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
 )
 
 // TestMultipleDatabases tests creating multiple databases with multiple schemas
@@ -18,6 +18,7 @@ import (
 // Property 2: Schema Creation Round-Trip
 // Property 3: Configuration Fidelity
 // Property 4: Transient Resource Handling
+// Property 12: Idempotent Apply
 func TestMultipleDatabases(t *testing.T) {
 	t.Parallel()
 
@@ -33,56 +34,57 @@ func TestMultipleDatabases(t *testing.T) {
 
 	tfDir := "../examples/multiple-databases-with-multiple-schemas"
 
-	databaseConfigs := map[string]interface{}{
-		"production": map[string]interface{}{
-			"name":    prodDbName,
-			"comment": "Terratest production database",
-			"schemas": []interface{}{
-				map[string]interface{}{
-					"name":    appSchemaName,
-					"comment": "Application schema",
-				},
-				map[string]interface{}{
-					"name":       auditSchemaName,
-					"comment":    "Audit logging schema",
-					"is_managed": true,
-				},
-			},
-		},
-		"development": map[string]interface{}{
-			"name":         devDbName,
-			"comment":      "Terratest development database",
-			"is_transient": true,
-			"schemas": []interface{}{
-				map[string]interface{}{
-					"name":    sandboxSchemaName,
-					"comment": "Developer sandbox",
-				},
-				map[string]interface{}{
-					"name":         testingSchemaName,
-					"comment":      "Test data schema",
-					"is_transient": true,
-				},
-			},
-		},
+	appSchema := dbconfig.NewSchemaConfig(appSchemaName)
+	appSchema.Comment = ptr("Application schema")
+
+	auditSchema := dbconfig.NewSchemaConfig(auditSchemaName)
+	auditSchema.Comment = ptr("Audit logging schema")
+	auditSchema.IsManaged = true
+
+	production := dbconfig.NewDatabaseConfig(prodDbName)
+	production.Comment = ptr("Terratest production database")
+	production.Schemas = []dbconfig.SchemaConfig{appSchema, auditSchema}
+
+	sandboxSchema := dbconfig.NewSchemaConfig(sandboxSchemaName)
+	sandboxSchema.Comment = ptr("Developer sandbox")
+
+	testingSchema := dbconfig.NewSchemaConfig(testingSchemaName)
+	testingSchema.Comment = ptr("Test data schema")
+	testingSchema.IsTransient = true
+
+	development := dbconfig.NewDatabaseConfig(devDbName)
+	development.Comment = ptr("Terratest development database")
+	development.IsTransient = true
+	development.Schemas = []dbconfig.SchemaConfig{sandboxSchema, testingSchema}
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"production":  production,
+		"development": development,
 	}
 
 	tfOptions := &terraform.Options{
 		TerraformDir: tfDir,
 		NoColor:      true,
-		Vars: map[string]interface{}{
-			"database_configs":            databaseConfigs,
-			"snowflake_organization_name": os.Getenv("SNOWFLAKE_ORGANIZATION_NAME"),
-			"snowflake_account_name":      os.Getenv("SNOWFLAKE_ACCOUNT_NAME"),
-			"snowflake_user":              os.Getenv("SNOWFLAKE_USER"),
-			"snowflake_role":              os.Getenv("SNOWFLAKE_ROLE"),
-			"snowflake_private_key":       os.Getenv("SNOWFLAKE_PRIVATE_KEY"),
-		},
+		Vars:         exampleVars(t, databaseConfigs),
+	}
+
+	if planOnly() {
+		plan := initAndPlanJSON(t, tfOptions)
+		requirePlannedDatabase(t, plan, "production", prodDbName, "Terratest production database", 1, false)
+		requirePlannedDatabase(t, plan, "development", devDbName, "Terratest development database", 1, true)
+		requirePlannedSchema(t, plan, "production."+appSchemaName, appSchemaName, "Application schema", false, false)
+		requirePlannedSchema(t, plan, "production."+auditSchemaName, auditSchemaName, "Audit logging schema", false, true)
+		requirePlannedSchema(t, plan, "development."+sandboxSchemaName, sandboxSchemaName, "Developer sandbox", false, false)
+		requirePlannedSchema(t, plan, "development."+testingSchemaName, testingSchemaName, "Test data schema", true, false)
+		return
 	}
 
 	defer terraform.Destroy(t, tfOptions)
 	terraform.InitAndApply(t, tfOptions)
 
+	// Property 12: Idempotent Apply - a second plan is empty
+	requireIdempotent(t, tfOptions)
+
 	time.Sleep(retrySleep)
 
 	db := openSnowflake(t)
@@ -99,15 +101,19 @@ func TestMultipleDatabases(t *testing.T) {
 	require.True(t, schemaExists(t, db, devDbName, testingSchemaName), "Expected schema %q in database %q", testingSchemaName, devDbName)
 
 	// Property 3: Configuration Fidelity - verify properties match
-	prodProps := fetchDatabaseProps(t, db, prodDbName)
-	require.Equal(t, prodDbName, prodProps.Name)
-	require.Contains(t, prodProps.Comment, "Terratest production database")
+	for _, cfg := range databaseConfigs {
+		requireDatabaseMatchesConfig(t, cfg, fetchDatabaseProps(t, db, cfg.Name))
+		for _, schema := range cfg.Schemas {
+			requireSchemaMatchesConfig(t, cfg, schema, fetchSchemaProps(t, db, cfg.Name, schema.Name))
+		}
+	}
 
 	auditProps := fetchSchemaProps(t, db, prodDbName, auditSchemaName)
 	require.True(t, auditProps.IsManagedAccess, "Expected audit schema to have managed access enabled")
 
 	// Property 4: Transient Resource Handling - verify transient database
 	devProps := fetchDatabaseProps(t, db, devDbName)
-	require.Equal(t, devDbName, devProps.Name)
-	require.Contains(t, devProps.Comment, "Terratest development database")
+	require.True(t, devProps.IsTransient, "Expected development database to be transient")
+	require.False(t, fetchDatabaseProps(t, db, prodDbName).IsTransient, "Expected production database to be permanent")
+	require.True(t, fetchSchemaProps(t, db, devDbName, sandboxSchemaName).IsTransient, "Expected schemas in a transient database to be transient")
 }
@@ -0,0 +1,107 @@
+// File: test/ownership_test.go
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+)
+
+// TestOwnershipTransfer tests moving database and schema ownership to
+// designated roles
+// Property 1: Database Creation Round-Trip
+// Property 2: Schema Creation Round-Trip
+// Property 5: Grant Fidelity
+// Property 8: Ownership Transfer
+func TestOwnershipTransfer(t *testing.T) {
+	t.Parallel()
+
+	retrySleep := 5 * time.Second
+	unique := strings.ToUpper(random.UniqueId())
+	dbName := fmt.Sprintf("TT_OWNED_%s", unique)
+	adminRole := fmt.Sprintf("TT_ADMIN_%s", unique)
+	loaderRole := fmt.Sprintf("TT_LOADER_%s", unique)
+	readerRole := fmt.Sprintf("TT_READER_%s", unique)
+
+	tfDir := "../examples/database-with-grants"
+
+	raw := dbconfig.NewSchemaConfig("RAW")
+	raw.OwnerRole = ptr(loaderRole)
+	raw.Grants = &dbconfig.SchemaGrants{UsageRoles: []string{readerRole}}
+
+	curated := dbconfig.NewSchemaConfig("CURATED")
+
+	app := dbconfig.NewDatabaseConfig(dbName)
+	app.OwnerRole = ptr(adminRole)
+	app.Grants = &dbconfig.DatabaseGrants{UsageRoles: []string{readerRole}}
+	app.Schemas = []dbconfig.SchemaConfig{raw, curated}
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"app": app,
+	}
+
+	tfOptions := &terraform.Options{
+		TerraformDir: tfDir,
+		NoColor:      true,
+		Vars:         exampleVars(t, databaseConfigs),
+	}
+
+	if planOnly() {
+		plan := initAndPlanJSON(t, tfOptions)
+		require.Equal(t, []string{"app"}, plannedKeys(plan, "snowflake_grant_ownership", "database"))
+		require.Equal(t, []string{"app.RAW"}, plannedKeys(plan, "snowflake_grant_ownership", "schema"))
+		return
+	}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	createRoles(t, db, adminRole, loaderRole, readerRole)
+	defer dropRoles(t, db, adminRole, loaderRole, readerRole)
+
+	// The first apply transfers ownership last, so the connected role does not
+	// need the new owner roles for it. Destroy does, and runs after this grant.
+	defer terraform.Destroy(t, tfOptions)
+	defer grantRolesToCurrentRole(t, db, adminRole, loaderRole)
+	terraform.InitAndApply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	// Inspecting and changing the transferred objects needs the new owners
+	grantRolesToCurrentRole(t, db, adminRole, loaderRole)
+
+	// Property 1: Database Creation Round-Trip
+	require.True(t, databaseExists(t, db, dbName), "Expected database %q to exist", dbName)
+
+	// Property 2: Schema Creation Round-Trip
+	require.True(t, schemaExists(t, db, dbName, "RAW"), "Expected schema RAW in database %q", dbName)
+
+	// Property 8: Ownership Transfer - owner_role owns the object, grants are copied
+	requireDatabaseMatchesConfig(t, app, fetchDatabaseProps(t, db, dbName))
+	requireSchemaMatchesConfig(t, app, raw, fetchSchemaProps(t, db, dbName, "RAW"))
+	require.NotEqual(t, loaderRole, fetchSchemaProps(t, db, dbName, "CURATED").Owner,
+		"Expected a schema without owner_role to keep its owner")
+	requireGrantsMatchConfig(t, db, app, dbconfig.AccessRolesConfig{})
+
+	// A later change adds a schema to the transferred database and a grant on
+	// the transferred schema, through the inherited owner roles
+	staging := dbconfig.NewSchemaConfig("STAGING")
+	raw.Grants.CreateTableRoles = []string{readerRole}
+	app.Schemas = []dbconfig.SchemaConfig{raw, curated, staging}
+	databaseConfigs["app"] = app
+	tfOptions.Vars = exampleVars(t, databaseConfigs)
+	terraform.Apply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	require.True(t, schemaExists(t, db, dbName, "STAGING"), "Expected schema STAGING in database %q", dbName)
+	requireDatabaseMatchesConfig(t, app, fetchDatabaseProps(t, db, dbName))
+	requireSchemaMatchesConfig(t, app, raw, fetchSchemaProps(t, db, dbName, "RAW"))
+	requireGrantsMatchConfig(t, db, app, dbconfig.AccessRolesConfig{})
+}
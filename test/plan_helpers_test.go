@@ -0,0 +1,349 @@
+// File: test/plan_helpers_test.go
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+)
+
+// planOnly reports whether tests should stop after terraform plan instead of
+// applying against Snowflake. Enabled with TERRATEST_PLAN_ONLY=true (or 1).
+func planOnly() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("TERRATEST_PLAN_ONLY"))
+	return enabled
+}
+
+// initAndPlanJSON runs terraform init and plan, then parses the saved plan
+// through terraform-json. In plan-only mode the provider is pointed at a
+// local fake endpoint with placeholder credentials, so no Snowflake account
+// is needed.
+func initAndPlanJSON(t *testing.T, options *terraform.Options) *tfjson.Plan {
+	t.Helper()
+
+	planOptions := *options
+	planOptions.PlanFilePath = filepath.Join(t.TempDir(), "tfplan")
+	if planOnly() {
+		usePlaceholderProvider(t, &planOptions)
+	}
+
+	planStruct := terraform.InitAndPlanAndShowWithStruct(t, &planOptions)
+	require.NotNil(t, planStruct.RawPlan.PlannedValues, "Plan has no planned values")
+	return &planStruct.RawPlan
+}
+
+// initAndPlanE runs terraform init and plan and returns the error, for tests
+// that expect the plan to be rejected. Plan-only mode uses the placeholder
+// provider as initAndPlanJSON does.
+func initAndPlanE(t *testing.T, options *terraform.Options) error {
+	t.Helper()
+
+	planOptions := *options
+	if planOnly() {
+		usePlaceholderProvider(t, &planOptions)
+	}
+
+	_, err := terraform.InitAndPlanE(t, &planOptions)
+	return err
+}
+
+// usePlaceholderProvider replaces the Snowflake connection settings of
+// options with placeholder credentials for the fake REST endpoint. The
+// connection variables of the examples are overridden when present and the
+// SNOWFLAKE_* environment variables cover the root module, whose provider is
+// configured from the environment.
+func usePlaceholderProvider(t *testing.T, options *terraform.Options) {
+	t.Helper()
+
+	endpoint := startFakeSnowflakeEndpoint(t)
+	_, privateKeyPEM := placeholderPrivateKey(t)
+
+	placeholders := map[string]string{
+		"organization_name": "PLACEHOLDER",
+		"account_name":      "PLACEHOLDER",
+		"user":              "PLACEHOLDER",
+		"role":              "PLACEHOLDER",
+		"private_key":       privateKeyPEM,
+	}
+
+	vars := map[string]interface{}{}
+	for k, v := range options.Vars {
+		vars[k] = v
+	}
+	envVars := map[string]string{}
+	for k, v := range options.EnvVars {
+		envVars[k] = v
+	}
+	for name, value := range placeholders {
+		if _, ok := vars["snowflake_"+name]; ok {
+			vars["snowflake_"+name] = value
+		}
+		envVars["SNOWFLAKE_"+strings.ToUpper(name)] = value
+	}
+	envVars["SNOWFLAKE_AUTHENTICATOR"] = "SNOWFLAKE_JWT"
+	envVars["SNOWFLAKE_HOST"] = endpoint.Hostname()
+	envVars["SNOWFLAKE_PORT"] = endpoint.Port()
+	envVars["SNOWFLAKE_PROTOCOL"] = endpoint.Scheme
+
+	options.Vars = vars
+	options.EnvVars = envVars
+}
+
+// requireIdempotent runs terraform plan -detailed-exitcode after an apply and
+// fails if the plan is not empty, listing every resource and attribute that
+// would still change. A non-empty plan right after apply means a perpetual
+// diff, such as a null comment the provider reads back as "".
+func requireIdempotent(t *testing.T, options *terraform.Options) {
+	t.Helper()
+
+	if terraform.PlanExitCode(t, options) == 0 {
+		return
+	}
+	plan := initAndPlanJSON(t, options)
+	require.Fail(t, "Expected an empty plan after apply", "Changes still planned:\n%s", describePlanChanges(plan))
+}
+
+// describePlanChanges renders the planned resource changes one per line, with
+// the before and after value of each changed attribute of an update
+func describePlanChanges(plan *tfjson.Plan) string {
+	var changes []*tfjson.ResourceChange
+	for _, rc := range plan.ResourceChanges {
+		if rc.Change == nil || rc.Change.Actions.NoOp() || rc.Change.Actions.Read() {
+			continue
+		}
+		changes = append(changes, rc)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Address < changes[j].Address })
+
+	var b strings.Builder
+	for _, rc := range changes {
+		var actions []string
+		for _, action := range rc.Change.Actions {
+			actions = append(actions, string(action))
+		}
+		fmt.Fprintf(&b, "%s (%s)\n", rc.Address, strings.Join(actions, ", "))
+		if !rc.Change.Actions.Update() {
+			continue
+		}
+		for _, attr := range changedAttributes(rc.Change) {
+			fmt.Fprintf(&b, "  %s\n", attr)
+		}
+	}
+	return b.String()
+}
+
+// changedAttributes lists the top-level attributes an update changes as
+// "name: before -> after", sorted by name. Values only known after apply
+// are shown as (known after apply).
+func changedAttributes(change *tfjson.Change) []string {
+	before, _ := change.Before.(map[string]interface{})
+	after, _ := change.After.(map[string]interface{})
+	unknown, _ := change.AfterUnknown.(map[string]interface{})
+
+	names := map[string]bool{}
+	for _, m := range []map[string]interface{}{before, after, unknown} {
+		for name := range m {
+			names[name] = true
+		}
+	}
+
+	var attrs []string
+	for name := range names {
+		if unknown[name] == true {
+			attrs = append(attrs, fmt.Sprintf("%s: %s -> (known after apply)", name, renderPlanValue(before[name])))
+			continue
+		}
+		if reflect.DeepEqual(before[name], after[name]) {
+			continue
+		}
+		attrs = append(attrs, fmt.Sprintf("%s: %s -> %s", name, renderPlanValue(before[name]), renderPlanValue(after[name])))
+	}
+	sort.Strings(attrs)
+	return attrs
+}
+
+// renderPlanValue shows a plan value as JSON, so null and "" stay distinct
+func renderPlanValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// requireErrorContains checks a terraform error for a message. Terraform
+// wraps diagnostics and prefixes each line with a box character, so both
+// sides are compared with whitespace collapsed.
+func requireErrorContains(t *testing.T, err error, message string) {
+	t.Helper()
+
+	require.Error(t, err)
+	output := strings.Join(strings.Fields(strings.ReplaceAll(err.Error(), "│", " ")), " ")
+	require.Contains(t, output, strings.Join(strings.Fields(message), " "))
+}
+
+// requirePreviewFeatureEnabled checks a resource the module uses behind a
+// provider preview feature. The schema of the provider installed by init must
+// have the resource and accept the feature in preview_features_enabled, and
+// the provider block in the planned configuration must enable it.
+func requirePreviewFeatureEnabled(t *testing.T, options *terraform.Options, plan *tfjson.Plan, resourceType, feature string) {
+	t.Helper()
+
+	output, err := terraform.RunTerraformCommandAndGetStdoutE(t, options, "providers", "schema", "-json")
+	require.NoError(t, err)
+	var schemas tfjson.ProviderSchemas
+	require.NoError(t, json.Unmarshal([]byte(output), &schemas))
+
+	var provider *tfjson.ProviderSchema
+	for address, schema := range schemas.Schemas {
+		if strings.HasSuffix(address, "snowflakedb/snowflake") {
+			provider = schema
+		}
+	}
+	require.NotNil(t, provider, "Expected the snowflakedb/snowflake provider schema")
+	require.Contains(t, provider.ResourceSchemas, resourceType)
+	attribute, ok := provider.ConfigSchema.Block.Attributes["preview_features_enabled"]
+	require.True(t, ok, "Expected the provider to have preview_features_enabled")
+	require.Contains(t, attribute.Description, feature)
+
+	require.NotNil(t, plan.Config, "Expected the configuration in the plan")
+	config, ok := plan.Config.ProviderConfigs["snowflake"]
+	require.True(t, ok, "Expected a snowflake provider block")
+	expression, ok := config.Expressions["preview_features_enabled"]
+	require.True(t, ok, "Expected the snowflake provider block to set preview_features_enabled")
+	require.Contains(t, expression.ConstantValue, feature)
+}
+
+// plannedResources returns the planned instances of a resource keyed by their
+// for_each key, searching the root module and all child modules.
+func plannedResources(plan *tfjson.Plan, resourceType, resourceName string) map[string]*tfjson.StateResource {
+	instances := map[string]*tfjson.StateResource{}
+	if plan.PlannedValues == nil {
+		return instances
+	}
+
+	var walk func(module *tfjson.StateModule)
+	walk = func(module *tfjson.StateModule) {
+		if module == nil {
+			return
+		}
+		for _, r := range module.Resources {
+			if r.Mode == tfjson.ManagedResourceMode && r.Type == resourceType && r.Name == resourceName {
+				instances[getString(r.Index)] = r
+			}
+		}
+		for _, child := range module.ChildModules {
+			walk(child)
+		}
+	}
+	walk(plan.PlannedValues.RootModule)
+
+	return instances
+}
+
+// plannedSchemaInstances returns the planned attribute values of every
+// resource instance whose for_each key belongs to a schema, keyed by address
+// with the schema key left out, e.g. snowflake_schema.this[] or
+// snowflake_grant_privileges_to_account_role.schema_monitor[/TT_READER].
+func plannedSchemaInstances(plan *tfjson.Plan, schemaKey string) map[string]map[string]interface{} {
+	instances := map[string]map[string]interface{}{}
+	if plan.PlannedValues == nil {
+		return instances
+	}
+
+	var walk func(module *tfjson.StateModule)
+	walk = func(module *tfjson.StateModule) {
+		if module == nil {
+			return
+		}
+		for _, r := range module.Resources {
+			key := getString(r.Index)
+			if r.Mode != tfjson.ManagedResourceMode || !strings.HasPrefix(key, schemaKey) {
+				continue
+			}
+			rest := strings.TrimPrefix(key, schemaKey)
+			if rest != "" && !strings.HasPrefix(rest, "/") && !strings.HasPrefix(rest, "_") {
+				continue
+			}
+			instances[fmt.Sprintf("%s.%s[%s]", r.Type, r.Name, rest)] = r.AttributeValues
+		}
+		for _, child := range module.ChildModules {
+			walk(child)
+		}
+	}
+	walk(plan.PlannedValues.RootModule)
+
+	return instances
+}
+
+// plannedKeys returns the sorted for_each keys planned for a resource
+func plannedKeys(plan *tfjson.Plan, resourceType, resourceName string) []string {
+	var keys []string
+	for k := range plannedResources(plan, resourceType, resourceName) {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// requirePlannedResource returns the planned attribute values of a single
+// resource instance, failing the test if it is not in the plan.
+func requirePlannedResource(t *testing.T, plan *tfjson.Plan, resourceType, resourceName, key string) map[string]interface{} {
+	t.Helper()
+
+	r, ok := plannedResources(plan, resourceType, resourceName)[key]
+	require.True(t, ok, "Expected %s.%s[%q] in plan, planned keys: %v", resourceType, resourceName, key, plannedKeys(plan, resourceType, resourceName))
+	return r.AttributeValues
+}
+
+// requirePlannedDatabase asserts the planned snowflake_database attributes
+func requirePlannedDatabase(t *testing.T, plan *tfjson.Plan, key, name, comment string, retention int, transient bool) {
+	t.Helper()
+
+	attrs := requirePlannedResource(t, plan, "snowflake_database", "this", key)
+	require.Equal(t, name, attrs["name"])
+	require.Equal(t, comment, getString(attrs["comment"]))
+	require.Equal(t, retention, getInt(attrs["data_retention_time_in_days"]))
+	require.Equal(t, transient, attrs["is_transient"])
+}
+
+// requirePlannedSchema asserts the planned snowflake_schema attributes
+func requirePlannedSchema(t *testing.T, plan *tfjson.Plan, key, name, comment string, transient, managed bool) {
+	t.Helper()
+
+	attrs := requirePlannedResource(t, plan, "snowflake_schema", "this", key)
+	require.Equal(t, name, attrs["name"])
+	require.Equal(t, comment, getString(attrs["comment"]))
+	require.Equal(t, transient, attrs["is_transient"])
+	require.Equal(t, managed, getBool(attrs["with_managed_access"]))
+}
+
+// requirePlannedGrant asserts the planned privileges and role of a
+// snowflake_grant_privileges_to_account_role instance
+func requirePlannedGrant(t *testing.T, plan *tfjson.Plan, resourceName, key, privilege, role string) {
+	t.Helper()
+
+	attrs := requirePlannedResource(t, plan, "snowflake_grant_privileges_to_account_role", resourceName, key)
+	require.Equal(t, role, attrs["account_role_name"])
+	require.Equal(t, []interface{}{privilege}, attrs["privileges"])
+}
+
+func getBool(v interface{}) bool {
+	switch val := v.(type) {
+	case bool:
+		return val
+	case string:
+		return strings.EqualFold(val, "true")
+	}
+	return false
+}
@@ -0,0 +1,524 @@
+// File: test/plan_test.go
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+)
+
+// TestPlanModuleWiring plans the root module directly and verifies the
+// for_each keys and attribute wiring of every resource in main.tf.
+// Nothing is applied, so the roles referenced by grants do not need to exist.
+func TestPlanModuleWiring(t *testing.T) {
+	t.Parallel()
+
+	unique := strings.ToUpper(random.UniqueId())
+	salesDbName := fmt.Sprintf("TT_SALES_%s", unique)
+	hrDbName := fmt.Sprintf("TT_HR_%s", unique)
+
+	rawSchema := dbconfig.NewSchemaConfig("RAW")
+	rawSchema.Comment = ptr("Raw sales data")
+	rawSchema.IsManaged = true
+	rawSchema.Grants = &dbconfig.SchemaGrants{
+		UsageRoles:            []string{"TT_READER"},
+		CreateFileFormatRoles: []string{"TT_LOADER"},
+		CreateStageRoles:      []string{"TT_LOADER"},
+		CreateTableRoles:      []string{"TT_WRITER"},
+		CreatePipeRoles:       []string{"TT_LOADER"},
+		CreateViewRoles:       []string{"TT_WRITER"},
+		MonitorRoles:          []string{"TT_READER"},
+		Privileges: map[string][]string{
+			"create tag": {"TT_WRITER"},
+			"MODIFY":     {"TT_WRITER", "TT_LOADER"},
+		},
+	}
+	rawSchema.FutureGrants = map[string]map[string][]string{
+		"TABLES":             {"SELECT": {"TT_READER"}, "INSERT": {"TT_WRITER"}},
+		"materialized views": {"select": {"TT_READER"}},
+	}
+	rawSchema.AllObjectsGrants = map[string]map[string][]string{
+		"TABLES": {"SELECT": {"TT_READER"}},
+	}
+	rawSchema.AccessRoles = &dbconfig.SchemaAccessRoles{RWGrantedToRoles: []string{"TT_LOADER"}}
+	rawSchema.OwnerRole = ptr("TT_LOADER")
+	rawSchema.OwnerOutboundPrivileges = "revoke"
+	rawSchema.StorageSerializationPolicy = ptr("compatible")
+	rawSchema.LogLevel = ptr("debug")
+	rawSchema.Tags = map[string]string{"TT_GOV.TAGS.SENSITIVITY": "restricted"}
+
+	sales := dbconfig.NewDatabaseConfig(salesDbName)
+	sales.Comment = ptr("Sales database")
+	sales.DataRetentionTimeInDays = 7
+	sales.OwnerRole = ptr("TT_ADMIN")
+	sales.MaxDataExtensionTimeInDays = ptr(30)
+	sales.DefaultDDLCollation = ptr("en-ci")
+	sales.LogLevel = ptr("WARN")
+	sales.EventTable = ptr("TT_OBS.TELEMETRY.EVENTS")
+	sales.Tags = map[string]string{
+		"TT_GOV.TAGS.COST_CENTER": "SALES-001",
+		"TT_GOV.TAGS.SENSITIVITY": "internal",
+	}
+	sales.Grants = &dbconfig.DatabaseGrants{
+		UsageRoles:              []string{"TT_READER", "TT_WRITER"},
+		CreateSchemaRoles:       []string{"TT_WRITER"},
+		ModifyRoles:             []string{"TT_LOADER"},
+		CreateDatabaseRoleRoles: []string{"TT_LOADER"},
+		Privileges:              map[string][]string{"MONITOR": {"TT_READER"}},
+	}
+	sales.Schemas = []dbconfig.SchemaConfig{rawSchema}
+	sales.DatabaseRoles = map[string]dbconfig.DatabaseRoleConfig{
+		"SALES_READ": {
+			Comment:               ptr("Read access to sales"),
+			GrantedToAccountRoles: []string{"TT_READER", "TT_WRITER"},
+			DatabasePrivileges:    []string{"usage"},
+			SchemaPrivileges:      map[string][]string{"RAW": {"USAGE", "MONITOR"}},
+		},
+	}
+
+	scratchSchema := dbconfig.NewSchemaConfig("SCRATCH")
+	scratchSchema.IsTransient = true
+	scratchSchema.AccessRoles = &dbconfig.SchemaAccessRoles{Enabled: ptr(false)}
+
+	hr := dbconfig.NewDatabaseConfig(hrDbName)
+	hr.IsTransient = true
+	hr.Schemas = []dbconfig.SchemaConfig{scratchSchema}
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"sales": sales,
+		"hr":    hr,
+	}
+
+	configsValue, err := databaseConfigs.TerraformValue()
+	require.NoError(t, err)
+
+	access := dbconfig.AccessRolesConfig{Enabled: true, NameTemplate: "AR_{database}_{schema}_{level}", ParentRole: ptr("SYSADMIN")}
+	accessValue, err := access.TerraformValue()
+	require.NoError(t, err)
+
+	tfOptions := &terraform.Options{
+		TerraformDir: "..",
+		NoColor:      true,
+		Vars: map[string]interface{}{
+			"database_configs": configsValue,
+			"access_roles":     accessValue,
+		},
+		EnvVars: map[string]string{
+			"SNOWFLAKE_AUTHENTICATOR": "SNOWFLAKE_JWT",
+		},
+	}
+
+	plan := initAndPlanJSON(t, tfOptions)
+
+	require.Equal(t, []string{"hr", "sales"}, plannedKeys(plan, "snowflake_database", "this"))
+	require.Equal(t, []string{"sales"}, plannedKeys(plan, "snowflake_grant_ownership", "database"))
+	ownership := requirePlannedResource(t, plan, "snowflake_grant_ownership", "database", "sales")
+	require.Equal(t, "TT_ADMIN", ownership["account_role_name"])
+	require.Equal(t, "COPY", ownership["outbound_privileges"])
+	require.Equal(t, []string{"sales.RAW"}, plannedKeys(plan, "snowflake_grant_ownership", "schema"))
+	ownership = requirePlannedResource(t, plan, "snowflake_grant_ownership", "schema", "sales.RAW")
+	require.Equal(t, "TT_LOADER", ownership["account_role_name"])
+	require.Equal(t, "REVOKE", ownership["outbound_privileges"])
+	requirePlannedDatabase(t, plan, "sales", salesDbName, "Sales database", 7, false)
+	requirePlannedDatabase(t, plan, "hr", hrDbName, "", 1, true)
+
+	require.Equal(t, []string{"hr.SCRATCH", "sales.RAW"}, plannedKeys(plan, "snowflake_schema", "this"))
+	requirePlannedSchema(t, plan, "sales.RAW", "RAW", "Raw sales data", false, true)
+	requirePlannedSchema(t, plan, "hr.SCRATCH", "SCRATCH", "", true, false)
+	salesDb := requirePlannedResource(t, plan, "snowflake_database", "this", "sales")
+	require.Equal(t, 30, getInt(salesDb["max_data_extension_time_in_days"]))
+	require.Equal(t, "en-ci", salesDb["default_ddl_collation"])
+	require.Equal(t, "COMPATIBLE", requirePlannedResource(t, plan, "snowflake_schema", "this", "sales.RAW")["storage_serialization_policy"])
+	require.Equal(t, "WARN", salesDb["log_level"])
+	require.Equal(t, "DEBUG", requirePlannedResource(t, plan, "snowflake_schema", "this", "sales.RAW")["log_level"])
+	require.Equal(t, []string{"sales"}, plannedKeys(plan, "snowflake_object_parameter", "database_event_table"))
+	require.Equal(t, "TT_OBS.TELEMETRY.EVENTS", requirePlannedResource(t, plan, "snowflake_object_parameter", "database_event_table", "sales")["value"])
+
+	require.Equal(t, []string{"sales/TT_GOV.TAGS.COST_CENTER", "sales/TT_GOV.TAGS.SENSITIVITY"}, plannedKeys(plan, "snowflake_tag_association", "database"))
+	dbTag := requirePlannedResource(t, plan, "snowflake_tag_association", "database", "sales/TT_GOV.TAGS.COST_CENTER")
+	require.Equal(t, "TT_GOV.TAGS.COST_CENTER", dbTag["tag_id"])
+	require.Equal(t, "SALES-001", dbTag["tag_value"])
+	require.Equal(t, "DATABASE", dbTag["object_type"])
+	require.Equal(t, []string{"sales.RAW/TT_GOV.TAGS.SENSITIVITY"}, plannedKeys(plan, "snowflake_tag_association", "schema"))
+	require.Equal(t, "restricted", requirePlannedResource(t, plan, "snowflake_tag_association", "schema", "sales.RAW/TT_GOV.TAGS.SENSITIVITY")["tag_value"])
+
+	require.Equal(t, []string{"sales/TT_READER", "sales/TT_WRITER"}, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "database_usage"))
+	requirePlannedGrant(t, plan, "database_usage", "sales/TT_READER", "USAGE", "TT_READER")
+	requirePlannedGrant(t, plan, "database_usage", "sales/TT_WRITER", "USAGE", "TT_WRITER")
+	requirePlannedGrant(t, plan, "database_create_schema", "sales/TT_WRITER", "CREATE SCHEMA", "TT_WRITER")
+	requirePlannedGrant(t, plan, "database_modify", "sales/TT_LOADER", "MODIFY", "TT_LOADER")
+	requirePlannedGrant(t, plan, "database_create_database_role", "sales/TT_LOADER", "CREATE DATABASE ROLE", "TT_LOADER")
+	require.Empty(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "database_monitor"))
+
+	requirePlannedGrant(t, plan, "schema_usage", "sales.RAW/TT_READER", "USAGE", "TT_READER")
+	requirePlannedGrant(t, plan, "schema_create_file_format", "sales.RAW/TT_LOADER", "CREATE FILE FORMAT", "TT_LOADER")
+	requirePlannedGrant(t, plan, "schema_create_stage", "sales.RAW/TT_LOADER", "CREATE STAGE", "TT_LOADER")
+	requirePlannedGrant(t, plan, "schema_create_table", "sales.RAW/TT_WRITER", "CREATE TABLE", "TT_WRITER")
+	requirePlannedGrant(t, plan, "schema_create_pipe", "sales.RAW/TT_LOADER", "CREATE PIPE", "TT_LOADER")
+	requirePlannedGrant(t, plan, "schema_create_view", "sales.RAW/TT_WRITER", "CREATE VIEW", "TT_WRITER")
+	requirePlannedGrant(t, plan, "schema_monitor", "sales.RAW/TT_READER", "MONITOR", "TT_READER")
+	require.Empty(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_create_task"))
+
+	require.Equal(t, []string{"sales/MONITOR/TT_READER"}, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "database_privileges"))
+	requirePlannedGrant(t, plan, "database_privileges", "sales/MONITOR/TT_READER", "MONITOR", "TT_READER")
+	require.Equal(t, []string{"sales.RAW/CREATE TAG/TT_WRITER", "sales.RAW/MODIFY/TT_LOADER", "sales.RAW/MODIFY/TT_WRITER"},
+		plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_privileges"))
+	requirePlannedGrant(t, plan, "schema_privileges", "sales.RAW/CREATE TAG/TT_WRITER", "CREATE TAG", "TT_WRITER")
+	require.Len(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_usage"), 1)
+
+	require.Equal(t, []string{"sales.RAW/MATERIALIZED VIEWS/SELECT/TT_READER", "sales.RAW/TABLES/INSERT/TT_WRITER", "sales.RAW/TABLES/SELECT/TT_READER"},
+		plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_future"))
+	requirePlannedGrant(t, plan, "schema_future", "sales.RAW/TABLES/SELECT/TT_READER", "SELECT", "TT_READER")
+	future := requirePlannedResource(t, plan, "snowflake_grant_privileges_to_account_role", "schema_future", "sales.RAW/MATERIALIZED VIEWS/SELECT/TT_READER")
+	require.Contains(t, fmt.Sprint(future["on_schema_object"]), "MATERIALIZED VIEWS")
+
+	require.Equal(t, []string{"sales.RAW/TABLES/SELECT/TT_READER"},
+		plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_all_objects"))
+	requirePlannedGrant(t, plan, "schema_all_objects", "sales.RAW/TABLES/SELECT/TT_READER", "SELECT", "TT_READER")
+	all := requirePlannedResource(t, plan, "snowflake_grant_privileges_to_account_role", "schema_all_objects", "sales.RAW/TABLES/SELECT/TT_READER")
+	require.Contains(t, fmt.Sprint(all["on_schema_object"]), "all")
+
+	require.Equal(t, []string{"sales.SALES_READ"}, plannedKeys(plan, "snowflake_database_role", "this"))
+	role := requirePlannedResource(t, plan, "snowflake_database_role", "this", "sales.SALES_READ")
+	require.Equal(t, "SALES_READ", role["name"])
+	require.Equal(t, salesDbName, role["database"])
+	require.Equal(t, "Read access to sales", role["comment"])
+	require.Equal(t, []string{"sales.SALES_READ/USAGE"}, plannedKeys(plan, "snowflake_grant_privileges_to_database_role", "database"))
+	require.Equal(t, []string{"sales.SALES_READ/RAW/MONITOR", "sales.SALES_READ/RAW/USAGE"},
+		plannedKeys(plan, "snowflake_grant_privileges_to_database_role", "schema"))
+	require.Equal(t, []interface{}{"MONITOR"},
+		requirePlannedResource(t, plan, "snowflake_grant_privileges_to_database_role", "schema", "sales.SALES_READ/RAW/MONITOR")["privileges"])
+	require.Equal(t, []string{"sales.SALES_READ/TT_READER", "sales.SALES_READ/TT_WRITER"}, plannedKeys(plan, "snowflake_grant_database_role", "to_account_role"))
+	require.Equal(t, "TT_WRITER",
+		requirePlannedResource(t, plan, "snowflake_grant_database_role", "to_account_role", "sales.SALES_READ/TT_WRITER")["parent_role_name"])
+
+	require.Equal(t, []string{"sales.RAW_OWNER", "sales.RAW_RO", "sales.RAW_RW"}, plannedKeys(plan, "snowflake_account_role", "access"))
+	require.Equal(t, "AR_"+salesDbName+"_RAW_RW", requirePlannedResource(t, plan, "snowflake_account_role", "access", "sales.RAW_RW")["name"])
+	require.Equal(t, []string{"sales.RAW_RO"}, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "access_database_usage"))
+	require.Equal(t, []string{"sales.RAW_OWNER", "sales.RAW_RO"}, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "access_schema"))
+	require.Equal(t, []string{"sales.RAW_RO_MATERIALIZED VIEWS", "sales.RAW_RO_TABLES", "sales.RAW_RO_VIEWS", "sales.RAW_RW_TABLES"},
+		plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "access_future"))
+	require.Equal(t, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "access_future"),
+		plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "access_all"))
+	hierarchy := plannedResources(plan, "snowflake_grant_account_role", "access_hierarchy")
+	require.Len(t, hierarchy, 3)
+	require.Equal(t, "AR_"+salesDbName+"_RAW_RO", hierarchy["sales.RAW_RO"].AttributeValues["role_name"])
+	require.Equal(t, "SYSADMIN", hierarchy["sales.RAW_OWNER"].AttributeValues["parent_role_name"])
+	require.Equal(t, []string{"sales.RAW_RW/TT_LOADER"}, plannedKeys(plan, "snowflake_grant_account_role", "access_members"))
+}
+
+// TestPlanRejectsUnknownPrivilege verifies the privileges maps are validated
+// against the privileges Snowflake accepts for each object type
+func TestPlanRejectsUnknownPrivilege(t *testing.T) {
+	t.Parallel()
+
+	raw := dbconfig.NewSchemaConfig("RAW")
+	raw.Grants = &dbconfig.SchemaGrants{Privileges: map[string][]string{"CREATE SCHEMA": {"TT_WRITER"}}}
+
+	invalidSchema := dbconfig.NewDatabaseConfig("TT_INVALID_SCHEMA_PRIVILEGE")
+	invalidSchema.Schemas = []dbconfig.SchemaConfig{raw}
+
+	invalidDatabase := dbconfig.NewDatabaseConfig("TT_INVALID_DATABASE_PRIVILEGE")
+	invalidDatabase.Grants = &dbconfig.DatabaseGrants{Privileges: map[string][]string{"SELECT": {"TT_READER"}}}
+
+	for name, cfg := range map[string]struct {
+		config  dbconfig.DatabaseConfig
+		message string
+	}{
+		"schema":   {invalidSchema, "Schema grants.privileges keys must be valid schema privileges"},
+		"database": {invalidDatabase, "Database grants.privileges keys must be one of"},
+	} {
+		configsValue, err := dbconfig.DatabaseConfigs{"invalid": cfg.config}.TerraformValue()
+		require.NoError(t, err)
+
+		err = initAndPlanE(t, &terraform.Options{
+			TerraformDir: "..",
+			NoColor:      true,
+			Vars: map[string]interface{}{
+				"database_configs": configsValue,
+			},
+		})
+		require.Error(t, err, "Expected invalid %s privilege to be rejected", name)
+		requireErrorContains(t, err, cfg.message)
+	}
+}
+
+// TestPlanSchemaFormsMatch sets every schema attribute on a schema in the
+// schemas list and on one in schemas_by_key, and verifies both plan the same
+// resources with the same values. The two forms declare separate object
+// types in variables.tf, so an attribute added to one but not the other, or
+// given a different default, shows up here.
+func TestPlanSchemaFormsMatch(t *testing.T) {
+	t.Parallel()
+
+	unique := strings.ToUpper(random.UniqueId())
+	listDbName := fmt.Sprintf("TT_FORMS_LIST_%s", unique)
+	keyDbName := fmt.Sprintf("TT_FORMS_KEY_%s", unique)
+
+	schema := dbconfig.NewSchemaConfig("RAW")
+	schema.Comment = ptr("Every attribute set")
+	schema.IsTransient = true
+	schema.IsManaged = true
+	schema.DataRetentionTimeInDays = ptr(3)
+	schema.OwnerRole = ptr("TT_LOADER")
+	schema.OwnerOutboundPrivileges = "REVOKE"
+	schema.ObjectParameters = dbconfig.ObjectParameters{
+		MaxDataExtensionTimeInDays: ptr(14),
+		DefaultDDLCollation:        ptr("en-ci"),
+		ExternalVolume:             ptr("TT_VOLUME"),
+		Catalog:                    ptr("TT_CATALOG"),
+		ReplaceInvalidCharacters:   ptr(true),
+		StorageSerializationPolicy: ptr("OPTIMIZED"),
+		LogLevel:                   ptr("INFO"),
+		TraceLevel:                 ptr("ON_EVENT"),
+	}
+	schema.Tags = map[string]string{"TT_GOV.TAGS.SENSITIVITY": "restricted"}
+	schema.Grants = &dbconfig.SchemaGrants{
+		UsageRoles:                  []string{"TT_READER"},
+		CreateFileFormatRoles:       []string{"TT_LOADER"},
+		CreateStageRoles:            []string{"TT_LOADER"},
+		CreateTableRoles:            []string{"TT_WRITER"},
+		CreatePipeRoles:             []string{"TT_LOADER"},
+		CreateViewRoles:             []string{"TT_WRITER"},
+		CreateMaterializedViewRoles: []string{"TT_WRITER"},
+		CreateSequenceRoles:         []string{"TT_WRITER"},
+		CreateFunctionRoles:         []string{"TT_WRITER"},
+		CreateProcedureRoles:        []string{"TT_WRITER"},
+		CreateStreamRoles:           []string{"TT_LOADER"},
+		CreateTaskRoles:             []string{"TT_LOADER"},
+		CreateDynamicTableRoles:     []string{"TT_WRITER"},
+		MonitorRoles:                []string{"TT_READER"},
+		Privileges:                  map[string][]string{"CREATE TAG": {"TT_WRITER"}},
+	}
+	schema.FutureGrants = map[string]map[string][]string{
+		"TABLES": {"SELECT": {"TT_READER"}},
+	}
+	schema.AllObjectsGrants = map[string]map[string][]string{
+		"VIEWS": {"SELECT": {"TT_READER"}},
+	}
+	schema.AccessRoles = &dbconfig.SchemaAccessRoles{
+		Enabled:             ptr(true),
+		ROGrantedToRoles:    []string{"TT_READER"},
+		RWGrantedToRoles:    []string{"TT_LOADER"},
+		OwnerGrantedToRoles: []string{"TT_WRITER"},
+	}
+
+	listDb := dbconfig.NewDatabaseConfig(listDbName)
+	listDb.Schemas = []dbconfig.SchemaConfig{schema}
+
+	keyDb := dbconfig.NewDatabaseConfig(keyDbName)
+	keyDb.SchemasByKey = map[string]dbconfig.SchemaConfig{"raw_key": schema}
+
+	configsValue, err := dbconfig.DatabaseConfigs{"list": listDb, "by_key": keyDb}.TerraformValue()
+	require.NoError(t, err)
+
+	accessValue, err := dbconfig.AccessRolesConfig{Enabled: true}.TerraformValue()
+	require.NoError(t, err)
+
+	plan := initAndPlanJSON(t, &terraform.Options{
+		TerraformDir: "..",
+		NoColor:      true,
+		Vars: map[string]interface{}{
+			"database_configs": configsValue,
+			"access_roles":     accessValue,
+		},
+	})
+
+	fromList := plannedSchemaInstances(plan, "list.RAW")
+	fromKey := plannedSchemaInstances(plan, "by_key.raw_key")
+	require.Contains(t, fromList, "snowflake_schema.this[]")
+
+	// Values that name the database differ only in the database name
+	normalize := func(instances map[string]map[string]interface{}) string {
+		data, err := json.Marshal(instances)
+		require.NoError(t, err)
+		return strings.ReplaceAll(string(data), listDbName, keyDbName)
+	}
+	require.JSONEq(t, normalize(fromList), normalize(fromKey))
+}
+
+// TestPlanGrantKeysDoNotCollide verifies grants whose key parts contain "_"
+// each get their own for_each key. Joined with "_", database key "a_b" with
+// role "C" and database key "a" with role "B_C" both produced "a_b_C" and
+// merge() kept only one of the USAGE grants.
+func TestPlanGrantKeysDoNotCollide(t *testing.T) {
+	t.Parallel()
+
+	unique := strings.ToUpper(random.UniqueId())
+
+	ab := dbconfig.NewDatabaseConfig(fmt.Sprintf("TT_AB_%s", unique))
+	ab.Grants = &dbconfig.DatabaseGrants{UsageRoles: []string{"C"}}
+
+	a := dbconfig.NewDatabaseConfig(fmt.Sprintf("TT_A_%s", unique))
+	a.Grants = &dbconfig.DatabaseGrants{UsageRoles: []string{"B_C"}}
+
+	// Schemas "RAW" with role "B_C" and "RAW_B" with role "C" both produced
+	// "a.RAW_B_C"
+	raw := dbconfig.NewSchemaConfig("RAW")
+	raw.Grants = &dbconfig.SchemaGrants{UsageRoles: []string{"B_C"}}
+	rawB := dbconfig.NewSchemaConfig("RAW_B")
+	rawB.Grants = &dbconfig.SchemaGrants{UsageRoles: []string{"C"}}
+	a.Schemas = []dbconfig.SchemaConfig{raw, rawB}
+
+	configsValue, err := dbconfig.DatabaseConfigs{"a_b": ab, "a": a}.TerraformValue()
+	require.NoError(t, err)
+
+	plan := initAndPlanJSON(t, &terraform.Options{
+		TerraformDir: "..",
+		NoColor:      true,
+		Vars: map[string]interface{}{
+			"database_configs": configsValue,
+		},
+	})
+
+	require.Equal(t, []string{"a/B_C", "a_b/C"}, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "database_usage"))
+	requirePlannedGrant(t, plan, "database_usage", "a/B_C", "USAGE", "B_C")
+	requirePlannedGrant(t, plan, "database_usage", "a_b/C", "USAGE", "C")
+
+	require.Equal(t, []string{"a.RAW/B_C", "a.RAW_B/C"}, plannedKeys(plan, "snowflake_grant_privileges_to_account_role", "schema_usage"))
+}
+
+// TestPlanRejectsSlashInKeys verifies the names used as key parts cannot
+// contain the "/" separator, and database keys cannot contain the "." that
+// separates them from schema and database role names
+func TestPlanRejectsSlashInKeys(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{"sales/eu", "sales.eu"} {
+		configsValue, err := dbconfig.DatabaseConfigs{key: dbconfig.NewDatabaseConfig("TT_SEPARATOR")}.TerraformValue()
+		require.NoError(t, err)
+
+		err = initAndPlanE(t, &terraform.Options{
+			TerraformDir: "..",
+			NoColor:      true,
+			Vars: map[string]interface{}{
+				"database_configs": configsValue,
+			},
+		})
+		require.Error(t, err, "Expected database key %q to be rejected", key)
+		requireErrorContains(t, err, "must not contain")
+	}
+}
+
+// TestPlanRejectsDuplicateNames verifies two schemas of a database, or two
+// databases, cannot resolve to the same Snowflake object. Unquoted names
+// resolve to upper case, so names that differ only in case collide.
+func TestPlanRejectsDuplicateNames(t *testing.T) {
+	t.Parallel()
+
+	duplicateSchemas := dbconfig.NewDatabaseConfig("TT_DUPLICATE_SCHEMAS")
+	duplicateSchemas.Schemas = []dbconfig.SchemaConfig{dbconfig.NewSchemaConfig("RAW"), dbconfig.NewSchemaConfig("RAW")}
+
+	caseSchemas := dbconfig.NewDatabaseConfig("TT_CASE_SCHEMAS")
+	caseSchemas.Schemas = []dbconfig.SchemaConfig{dbconfig.NewSchemaConfig("raw"), dbconfig.NewSchemaConfig("RAW")}
+
+	for name, cfg := range map[string]struct {
+		configs dbconfig.DatabaseConfigs
+		message string
+	}{
+		"schema": {
+			dbconfig.DatabaseConfigs{"invalid": duplicateSchemas},
+			"Schema names must be unique within a database",
+		},
+		"schema case": {
+			dbconfig.DatabaseConfigs{"invalid": caseSchemas},
+			`Unquoted names are case-insensitive, so "raw" and "RAW" are the same schema`,
+		},
+		"database": {
+			dbconfig.DatabaseConfigs{"sales": dbconfig.NewDatabaseConfig("TT_SALES"), "sales_copy": dbconfig.NewDatabaseConfig("TT_SALES")},
+			"Each database name must be used by only one database_configs key",
+		},
+		"database case": {
+			dbconfig.DatabaseConfigs{"sales": dbconfig.NewDatabaseConfig("TT_SALES"), "sales_lower": dbconfig.NewDatabaseConfig("tt_sales")},
+			`Unquoted names are case-insensitive, so "sales_db" and "SALES_DB" are the same database`,
+		},
+	} {
+		configsValue, err := cfg.configs.TerraformValue()
+		require.NoError(t, err)
+
+		err = initAndPlanE(t, &terraform.Options{
+			TerraformDir: "..",
+			NoColor:      true,
+			Vars: map[string]interface{}{
+				"database_configs": configsValue,
+			},
+		})
+		require.Error(t, err, "Expected duplicate %s names to be rejected", name)
+		requireErrorContains(t, err, cfg.message)
+	}
+}
+
+// TestPlanRejectsOverlappingGrants verifies a role cannot be granted the same
+// privilege through both a grants role list and the privileges map
+func TestPlanRejectsOverlappingGrants(t *testing.T) {
+	t.Parallel()
+
+	database := dbconfig.NewDatabaseConfig("TT_OVERLAP_DATABASE")
+	database.Grants = &dbconfig.DatabaseGrants{
+		MonitorRoles: []string{"TT_READER"},
+		Privileges:   map[string][]string{"monitor": {"TT_READER"}},
+	}
+
+	raw := dbconfig.NewSchemaConfig("RAW")
+	raw.Grants = &dbconfig.SchemaGrants{
+		CreateTableRoles: []string{"TT_WRITER"},
+		Privileges:       map[string][]string{"CREATE TABLE": {"TT_WRITER"}},
+	}
+	schema := dbconfig.NewDatabaseConfig("TT_OVERLAP_SCHEMA")
+	schema.Schemas = []dbconfig.SchemaConfig{raw}
+
+	for name, cfg := range map[string]struct {
+		config  dbconfig.DatabaseConfig
+		message string
+	}{
+		"database": {database, "same database privilege through both a grants role list"},
+		"schema":   {schema, "same schema privilege through both a grants role list"},
+	} {
+		configsValue, err := dbconfig.DatabaseConfigs{"invalid": cfg.config}.TerraformValue()
+		require.NoError(t, err)
+
+		err = initAndPlanE(t, &terraform.Options{
+			TerraformDir: "..",
+			NoColor:      true,
+			Vars: map[string]interface{}{
+				"database_configs": configsValue,
+			},
+		})
+		require.Error(t, err, "Expected overlapping %s grants to be rejected", name)
+		requireErrorContains(t, err, cfg.message)
+	}
+}
+
+// TestPlanRejectsDuplicateAccessRoleNames checks that two schemas whose names
+// render the same access role name through the default template are refused
+// instead of planning one role twice
+func TestPlanRejectsDuplicateAccessRoleNames(t *testing.T) {
+	t.Parallel()
+
+	ab := dbconfig.NewDatabaseConfig("TT_A_B")
+	ab.Schemas = []dbconfig.SchemaConfig{dbconfig.NewSchemaConfig("C")}
+	a := dbconfig.NewDatabaseConfig("TT_A")
+	a.Schemas = []dbconfig.SchemaConfig{dbconfig.NewSchemaConfig("B_C")}
+
+	configsValue, err := dbconfig.DatabaseConfigs{"a_b": ab, "a": a}.TerraformValue()
+	require.NoError(t, err)
+	accessValue, err := dbconfig.AccessRolesConfig{Enabled: true}.TerraformValue()
+	require.NoError(t, err)
+
+	err = initAndPlanE(t, &terraform.Options{
+		TerraformDir: "..",
+		NoColor:      true,
+		Vars: map[string]interface{}{
+			"database_configs": configsValue,
+			"access_roles":     accessValue,
+		},
+	})
+	require.Error(t, err, "Expected TT_A_B.C and TT_A.B_C to be rejected as both render TT_A_B_C_RO")
+	requireErrorContains(t, err, "Two schemas render the same access role name from access_roles.name_template.")
+}
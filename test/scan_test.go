@@ -0,0 +1,87 @@
+// File: test/scan_test.go
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/show"
+)
+
+// scanTestRow covers every field kind the SHOW scanner supports
+type scanTestRow struct {
+	CreatedOn   time.Time `sf:"created_on"`
+	Name        string    `sf:"name"`
+	Retention   int       `sf:"retention_time"`
+	IsDefault   bool      `sf:"is_default"`
+	IsTransient bool      `sf:"options,contains=TRANSIENT"`
+	Budget      *string   `sf:"budget"`
+	Origin      string    `sf:"origin"`
+	Missing     string    `sf:"not_a_column"`
+	Ignored     string
+}
+
+// TestScanShowRows verifies SHOW output is mapped onto tagged struct fields,
+// including NULLs, Y/N booleans, timestamps and numeric strings
+func TestScanShowRows(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	createdOn := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	catalog.addDatabase(fakeDatabase{Name: "TT_SCAN_A", RetentionTime: 3, IsTransient: true, CreatedOn: createdOn})
+	catalog.addDatabase(fakeDatabase{Name: "TT_SCAN_B", RetentionTime: 0})
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	var rows []scanTestRow
+	require.NoError(t, show.Query(db, "SHOW DATABASES LIKE 'TT_SCAN_%';", &rows))
+	require.Len(t, rows, 2)
+
+	require.Equal(t, createdOn, rows[0].CreatedOn)
+	require.Equal(t, "TT_SCAN_A", rows[0].Name)
+	require.Equal(t, 3, rows[0].Retention)
+	require.False(t, rows[0].IsDefault)
+	require.True(t, rows[0].IsTransient)
+	require.Nil(t, rows[0].Budget)
+	require.Empty(t, rows[0].Missing)
+
+	require.Equal(t, "TT_SCAN_B", rows[1].Name)
+	require.Equal(t, 0, rows[1].Retention)
+	require.False(t, rows[1].IsTransient)
+}
+
+// TestScanShowRowsRejectsBadInput verifies conversion failures and invalid
+// destinations are reported as errors
+func TestScanShowRowsRejectsBadInput(t *testing.T) {
+	catalog := useFakeSnowflake(t)
+	catalog.addDatabase(fakeDatabase{Name: "TT_SCAN"})
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	var notSlice scanTestRow
+	require.ErrorContains(t, show.Query(db, "SHOW DATABASES;", &notSlice), "pointer to a slice of structs")
+
+	var badInt []struct {
+		Name int `sf:"name"`
+	}
+	require.ErrorContains(t, show.Query(db, "SHOW DATABASES;", &badInt), `invalid integer "TT_SCAN"`)
+
+	catalog.addDatabase(fakeDatabase{Name: "TT_SCAN_FRACTION", Comment: "1.5"})
+	var fraction []struct {
+		Comment int `sf:"comment"`
+	}
+	require.ErrorContains(t, show.Query(db, "SHOW DATABASES LIKE 'TT_SCAN_FRACTION';", &fraction), `invalid integer "1.5"`)
+
+	catalog.addDatabase(fakeDatabase{Name: "TT_SCAN_WHOLE", Comment: "3.000"})
+	var whole []struct {
+		Comment int `sf:"comment"`
+	}
+	require.NoError(t, show.Query(db, "SHOW DATABASES LIKE 'TT_SCAN_WHOLE';", &whole))
+	require.Equal(t, 3, whole[0].Comment)
+
+	var badBool []struct {
+		Name bool `sf:"name"`
+	}
+	require.ErrorContains(t, show.Query(db, "SHOW DATABASES;", &badBool), `invalid boolean "TT_SCAN"`)
+}
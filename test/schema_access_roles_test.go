@@ -0,0 +1,107 @@
+// File: test/schema_access_roles_test.go
+package test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+)
+
+// TestSchemaAccessRoles tests the generated RO/RW/OWNER access roles
+// Property 1: Database Creation Round-Trip
+// Property 2: Schema Creation Round-Trip
+// Property 5: Grant Fidelity
+// Property 7: Access Role Hierarchy
+func TestSchemaAccessRoles(t *testing.T) {
+	t.Parallel()
+
+	retrySleep := 5 * time.Second
+	unique := strings.ToUpper(random.UniqueId())
+	dbName := fmt.Sprintf("TT_AR_%s", unique)
+	analystRole := fmt.Sprintf("TT_ANALYST_%s", unique)
+
+	tfDir := "../examples/schema-access-roles"
+
+	curated := dbconfig.NewSchemaConfig("CURATED")
+	curated.Comment = ptr("Terratest curated schema")
+	curated.AccessRoles = &dbconfig.SchemaAccessRoles{ROGrantedToRoles: []string{analystRole}}
+
+	scratch := dbconfig.NewSchemaConfig("SCRATCH")
+	scratch.AccessRoles = &dbconfig.SchemaAccessRoles{Enabled: ptr(false)}
+
+	sales := dbconfig.NewDatabaseConfig(dbName)
+	sales.Schemas = []dbconfig.SchemaConfig{curated, scratch}
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"sales": sales,
+	}
+
+	access := dbconfig.AccessRolesConfig{Enabled: true, NameTemplate: "{database}_{schema}_{level}"}
+	if role := os.Getenv("SNOWFLAKE_ROLE"); role != "" {
+		access.ParentRole = ptr(role)
+	}
+	accessValue, err := access.TerraformValue()
+	require.NoError(t, err)
+
+	vars := exampleVars(t, databaseConfigs)
+	vars["access_roles"] = accessValue
+
+	tfOptions := &terraform.Options{
+		TerraformDir: tfDir,
+		NoColor:      true,
+		Vars:         vars,
+	}
+
+	if planOnly() {
+		plan := initAndPlanJSON(t, tfOptions)
+		require.Equal(t, []string{"sales.CURATED_OWNER", "sales.CURATED_RO", "sales.CURATED_RW"},
+			plannedKeys(plan, "snowflake_account_role", "access"))
+		require.Len(t, plannedKeys(plan, "snowflake_grant_account_role", "access_members"), 1)
+		return
+	}
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	createRoles(t, db, analystRole)
+	defer dropRoles(t, db, analystRole)
+
+	defer terraform.Destroy(t, tfOptions)
+	terraform.InitAndApply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	// Property 1: Database Creation Round-Trip
+	require.True(t, databaseExists(t, db, dbName), "Expected database %q to exist", dbName)
+
+	// Property 2: Schema Creation Round-Trip
+	require.True(t, schemaExists(t, db, dbName, "CURATED"), "Expected schema CURATED in database %q", dbName)
+
+	// Property 7: Access Role Hierarchy
+	requireAccessRolesMatchConfig(t, db, access, sales, curated)
+
+	// Property 5: Grant Fidelity - the generated roles hold exactly their bundles
+	requireGrantsMatchConfig(t, db, sales, access)
+
+	roleNames := terraform.OutputMapOfObjects(t, tfOptions, "access_role_names")
+	curatedRoles, ok := roleNames["sales"].(map[string]interface{})["CURATED"].(map[string]interface{})
+	require.True(t, ok, "Expected generated roles for CURATED in access_role_names output: %v", roleNames)
+	for _, level := range dbconfig.AccessRoleLevels {
+		require.Equal(t, access.RoleName(dbName, "CURATED", level), curatedRoles[level])
+	}
+	_, hasScratch := roleNames["sales"].(map[string]interface{})["SCRATCH"]
+	require.False(t, hasScratch, "Expected no generated roles for SCRATCH")
+
+	// RW reaches the schema only through RO
+	rw := access.RoleName(dbName, "CURATED", "RW")
+	rwGrants := fetchGrantsToRole(t, db, rw)
+	require.True(t, hasGrantOn(rwGrants, "USAGE", "ROLE", access.RoleName(dbName, "CURATED", "RO")), "Expected RO to be granted to %s", rw)
+	require.False(t, hasGrantOn(rwGrants, "CREATE TABLE", "SCHEMA", dbName+".CURATED"), "Expected %s to have no CREATE TABLE", rw)
+}
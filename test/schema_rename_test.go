@@ -0,0 +1,83 @@
+// File: test/schema_rename_test.go
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+	"github.com/subhamay-bhattacharyya-tf/terraform-snowflake-database-schema/test/dbconfig"
+)
+
+// TestSchemaRename tests that renaming a schema declared in schemas_by_key
+// renames the existing schema instead of replacing it
+// Property 1: Database Creation Round-Trip
+// Property 2: Schema Creation Round-Trip
+// Property 11: Rename In Place
+func TestSchemaRename(t *testing.T) {
+	t.Parallel()
+
+	retrySleep := 5 * time.Second
+	unique := strings.ToUpper(random.UniqueId())
+	dbName := fmt.Sprintf("TT_RENAME_%s", unique)
+
+	tfDir := "../examples/database-with-schemas-by-key"
+
+	staging := dbconfig.NewSchemaConfig("STAGING")
+	staging.Comment = ptr("Data landed from source systems")
+
+	analytics := dbconfig.NewDatabaseConfig(dbName)
+	analytics.SchemasByKey = map[string]dbconfig.SchemaConfig{
+		"staging": staging,
+		"marts":   dbconfig.NewSchemaConfig("MARTS"),
+	}
+
+	databaseConfigs := dbconfig.DatabaseConfigs{
+		"analytics": analytics,
+	}
+
+	tfOptions := &terraform.Options{
+		TerraformDir: tfDir,
+		NoColor:      true,
+		Vars:         exampleVars(t, databaseConfigs),
+	}
+
+	if planOnly() {
+		plan := initAndPlanJSON(t, tfOptions)
+		require.Equal(t, []string{"analytics.marts", "analytics.staging"}, plannedKeys(plan, "snowflake_schema", "this"))
+		requirePlannedSchema(t, plan, "analytics.staging", "STAGING", "Data landed from source systems", false, false)
+		return
+	}
+
+	defer terraform.Destroy(t, tfOptions)
+	terraform.InitAndApply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	db := openSnowflake(t)
+	defer func() { _ = db.Close() }()
+
+	// Property 1: Database Creation Round-Trip
+	require.True(t, databaseExists(t, db, dbName), "Expected database %q to exist", dbName)
+
+	// Property 2: Schema Creation Round-Trip
+	before := fetchSchemaProps(t, db, dbName, "STAGING")
+	requireSchemaMatchesConfig(t, analytics, staging, before)
+
+	// Property 11: Rename In Place - same key, new name, same object
+	staging.Name = "LANDING"
+	analytics.SchemasByKey["staging"] = staging
+	databaseConfigs["analytics"] = analytics
+
+	tfOptions.Vars = exampleVars(t, databaseConfigs)
+	terraform.Apply(t, tfOptions)
+
+	time.Sleep(retrySleep)
+
+	after := requireSchemaRenamed(t, db, dbName, before, "LANDING")
+	requireSchemaMatchesConfig(t, analytics, staging, after)
+}
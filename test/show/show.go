@@ -0,0 +1,325 @@
+// File: test/show/show.go
+
+// Package show reads the output of Snowflake SHOW statements into structs,
+// and has the row types for the databases, schemas and grants the module
+// manages.
+package show
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DatabaseProps is a SHOW DATABASES row. Budget and ResourceGroup are nil
+// when Snowflake reports NULL. DroppedOn is only reported by SHOW DATABASES
+// HISTORY and is nil for databases that still exist.
+type DatabaseProps struct {
+	CreatedOn               time.Time  `sf:"created_on"`
+	Name                    string     `sf:"name"`
+	IsDefault               bool       `sf:"is_default"`
+	IsCurrent               bool       `sf:"is_current"`
+	Origin                  string     `sf:"origin"`
+	Owner                   string     `sf:"owner"`
+	Comment                 string     `sf:"comment"`
+	Options                 string     `sf:"options"`
+	IsTransient             bool       `sf:"options,contains=TRANSIENT"`
+	DataRetentionTimeInDays int        `sf:"retention_time"`
+	Kind                    string     `sf:"kind"`
+	Budget                  *string    `sf:"budget"`
+	OwnerRoleType           string     `sf:"owner_role_type"`
+	ResourceGroup           *string    `sf:"resource_group"`
+	DroppedOn               *time.Time `sf:"dropped_on"`
+}
+
+// IsShared reports whether the database was created from a share: Snowflake
+// sets origin to the share and kind to IMPORTED DATABASE
+func (p DatabaseProps) IsShared() bool {
+	return p.Origin != "" || strings.EqualFold(p.Kind, "IMPORTED DATABASE")
+}
+
+// SchemaProps is a SHOW SCHEMAS row. Budget and ResourceGroup are nil when
+// Snowflake reports NULL. DroppedOn is only reported by SHOW SCHEMAS HISTORY
+// and is nil for schemas that still exist.
+type SchemaProps struct {
+	CreatedOn               time.Time  `sf:"created_on"`
+	Name                    string     `sf:"name"`
+	IsDefault               bool       `sf:"is_default"`
+	IsCurrent               bool       `sf:"is_current"`
+	DatabaseName            string     `sf:"database_name"`
+	Owner                   string     `sf:"owner"`
+	Comment                 string     `sf:"comment"`
+	Options                 string     `sf:"options"`
+	IsTransient             bool       `sf:"options,contains=TRANSIENT"`
+	IsManagedAccess         bool       `sf:"options,contains=MANAGED ACCESS"`
+	DataRetentionTimeInDays int        `sf:"retention_time"`
+	OwnerRoleType           string     `sf:"owner_role_type"`
+	Budget                  *string    `sf:"budget"`
+	ResourceGroup           *string    `sf:"resource_group"`
+	DroppedOn               *time.Time `sf:"dropped_on"`
+}
+
+// GrantInfo represents a grant privilege record
+type GrantInfo struct {
+	Privilege string `sf:"privilege"`
+	GrantedOn string `sf:"granted_on"`
+	Name      string `sf:"name"`
+	GrantedTo string `sf:"granted_to"`
+	Grantee   string `sf:"grantee_name"`
+}
+
+// IsDatabaseRole reports whether the grantee is a database role
+func (g GrantInfo) IsDatabaseRole() bool {
+	return strings.EqualFold(g.GrantedTo, "DATABASE_ROLE")
+}
+
+// GranteeRoleName returns the grantee without the database qualifier that
+// Snowflake adds for database roles (DB.ROLE becomes ROLE)
+func (g GrantInfo) GranteeRoleName() string {
+	return unqualifiedName(g.Grantee)
+}
+
+// unqualifiedName returns the last part of a dotted identifier, unquoted
+func unqualifiedName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.Trim(name, `"`)
+}
+
+// FutureGrantInfo represents a SHOW FUTURE GRANTS record. GrantOn is the
+// singular object type, e.g. TABLE or MATERIALIZED_VIEW.
+type FutureGrantInfo struct {
+	Privilege string `sf:"privilege"`
+	GrantOn   string `sf:"grant_on"`
+	Name      string `sf:"name"`
+	GrantTo   string `sf:"grant_to"`
+	Grantee   string `sf:"grantee_name"`
+}
+
+// ObjectTypePlural returns the object type in the plural form used by
+// future_grants, e.g. MATERIALIZED_VIEW becomes MATERIALIZED VIEWS
+func (g FutureGrantInfo) ObjectTypePlural() string {
+	return PluralObjectType(g.GrantOn)
+}
+
+// PluralObjectType converts a singular object type as reported by SHOW
+// output (TABLE, MATERIALIZED_VIEW) into the plural grant form
+func PluralObjectType(singular string) string {
+	return strings.ReplaceAll(strings.ToUpper(singular), "_", " ") + "S"
+}
+
+// timestampLayouts are the formats Snowflake uses for timestamp columns
+// when they are returned as text
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999 -07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// tagField maps a SHOW output column onto a struct field. The tag has the
+// form `sf:"column"` or `sf:"column,contains=TEXT"`; the latter sets a bool
+// field when the column value contains TEXT (e.g. options lists).
+type tagField struct {
+	index    int
+	column   string
+	contains string
+}
+
+func parseFields(t reflect.Type) []tagField {
+	var fields []tagField
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("sf")
+		if !ok || tag == "" || tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		f := tagField{index: i, column: parts[0]}
+		for _, opt := range parts[1:] {
+			if strings.HasPrefix(opt, "contains=") {
+				f.contains = strings.TrimPrefix(opt, "contains=")
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// Query runs a SHOW (or SELECT) statement and scans every row into dest
+func Query(db *sql.DB, q string, dest interface{}) error {
+	rows, err := db.Query(q)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return ScanRows(rows, dest)
+}
+
+// ScanRows reads all remaining rows into dest, which must be a pointer to
+// a slice of structs. Columns are matched to fields by their `sf` tag;
+// columns without a field and fields without a column are ignored.
+func ScanRows(rows *sql.Rows, dest interface{}) error {
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice || slice.Elem().Type().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ScanRows: dest must be a pointer to a slice of structs, got %T", dest)
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	colIdx := make(map[string]int, len(cols))
+	for i, col := range cols {
+		colIdx[strings.ToLower(col)] = i
+	}
+
+	fields := parseFields(elemType)
+
+	for rows.Next() {
+		values := make([]interface{}, len(cols))
+		valuePtrs := make([]interface{}, len(cols))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return err
+		}
+
+		elem := reflect.New(elemType).Elem()
+		for _, f := range fields {
+			i, ok := colIdx[f.column]
+			if !ok {
+				continue
+			}
+			field := elem.Field(f.index)
+			if f.contains != "" {
+				if field.Kind() != reflect.Bool {
+					return fmt.Errorf("ScanRows: %s.%s must be bool to use contains", elemType.Name(), elemType.Field(f.index).Name)
+				}
+				field.SetBool(strings.Contains(strings.ToUpper(getString(values[i])), strings.ToUpper(f.contains)))
+				continue
+			}
+			if err := setValue(field, values[i]); err != nil {
+				return fmt.Errorf("ScanRows: column %s into %s.%s: %w", f.column, elemType.Name(), elemType.Field(f.index).Name, err)
+			}
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+	return rows.Err()
+}
+
+// setValue converts a raw column value into the field. NULL leaves the
+// field at its zero value (nil for pointer fields).
+func setValue(field reflect.Value, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		p := reflect.New(field.Type().Elem())
+		if err := setValue(p.Elem(), v); err != nil {
+			return err
+		}
+		field.Set(p)
+		return nil
+	}
+
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		ts, err := parseTime(v)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(ts))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(getString(v))
+	case reflect.Bool:
+		b, err := parseBool(v)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s := strings.TrimSpace(getString(v))
+		if s == "" {
+			return nil
+		}
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			// Accept whole numbers with a fraction part, such as "3.000",
+			// but never truncate one that is not whole
+			f, ferr := strconv.ParseFloat(s, 64)
+			if ferr != nil || f != math.Trunc(f) || math.IsInf(f, 0) {
+				return fmt.Errorf("invalid integer %q", s)
+			}
+			i = int64(f)
+		}
+		field.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		s := strings.TrimSpace(getString(v))
+		if s == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+func parseBool(v interface{}) (bool, error) {
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+	switch strings.ToUpper(strings.TrimSpace(getString(v))) {
+	case "TRUE", "Y", "YES", "ON", "1":
+		return true, nil
+	case "FALSE", "N", "NO", "OFF", "0", "":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", getString(v))
+}
+
+func parseTime(v interface{}) (time.Time, error) {
+	if ts, ok := v.(time.Time); ok {
+		return ts, nil
+	}
+	s := strings.TrimSpace(getString(v))
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timestampLayouts {
+		if ts, err := time.Parse(layout, s); err == nil {
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+}
+
+func getString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", v)
+}
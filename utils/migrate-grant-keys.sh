@@ -0,0 +1,45 @@
+#!/usr/bin/env bash
+# -----------------------------------------------------------------------------
+# Move grants created by module versions that joined for_each keys with "_"
+# to the "/"-joined keys of the current version, without revoking them.
+#
+# Usage: utils/migrate-grant-keys.sh <module address> [--dry-run]
+#   e.g. utils/migrate-grant-keys.sh module.databases
+#
+# Run it in the calling configuration after `terraform init -upgrade` and
+# before the first plan with the new version. Needs terraform and jq.
+#
+# An old key cannot be split on "_" alone ("a_b_C" is database "a_b" with role
+# "C" or database "a" with role "B_C"), so the role is read from the granted
+# account_role_name in state and the rest of the key is the database or schema
+# key. Keys that already contain "/" are left alone, so running it twice is
+# harmless.
+# -----------------------------------------------------------------------------
+
+set -euo pipefail
+
+if [[ $# -lt 1 ]]; then
+  echo "usage: $0 <module address> [--dry-run]" >&2
+  exit 1
+fi
+
+module_address="$1"
+dry_run="${2:-}"
+
+terraform show -json | jq -r --arg address "$module_address" '
+  [.values.root_module | recurse(.child_modules[]?)]
+  | .[] | select(.address == $address) | .resources[]?
+  | select(.mode == "managed" and .type == "snowflake_grant_privileges_to_account_role")
+  | select(.name | IN("database_usage", "schema_usage", "schema_create_file_format", "schema_create_stage", "schema_create_table", "schema_create_pipe"))
+  | select(.index | contains("/") | not)
+  | (.values.account_role_name | ltrimstr("\"") | rtrimstr("\"")) as $role
+  | select(.index | endswith("_" + $role))
+  | (.index | .[0:(length - ($role | length) - 1)]) as $parent
+  | "\(.address)\t\($address).\(.type).\(.name)[\"\($parent)/\($role)\"]"
+' | while IFS=$'\t' read -r from to; do
+  if [[ "$dry_run" == "--dry-run" ]]; then
+    echo "terraform state mv '$from' '$to'"
+  else
+    terraform state mv "$from" "$to" </dev/null
+  fi
+done
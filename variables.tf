@@ -11,29 +11,163 @@ variable "database_configs" {
     comment                     = optional(string, null)
     data_retention_time_in_days = optional(number, 1)
     is_transient                = optional(bool, false)
+    owner_role                  = optional(string, null)
+    owner_outbound_privileges   = optional(string, "COPY")
+    # Parameters left null inherit the account value
+    max_data_extension_time_in_days = optional(number, null)
+    default_ddl_collation           = optional(string, null)
+    external_volume                 = optional(string, null)
+    catalog                         = optional(string, null)
+    replace_invalid_characters      = optional(bool, null)
+    storage_serialization_policy    = optional(string, null)
+    log_level                       = optional(string, null)
+    trace_level                     = optional(string, null)
+    event_table                     = optional(string, null)
+    tags                            = optional(map(string), {})
     grants = optional(object({
-      usage_roles = optional(list(string), [])
-    }), { usage_roles = [] })
+      usage_roles                = optional(list(string), [])
+      monitor_roles              = optional(list(string), [])
+      create_schema_roles        = optional(list(string), [])
+      modify_roles               = optional(list(string), [])
+      create_database_role_roles = optional(list(string), [])
+      privileges                 = optional(map(list(string)), {})
+      }), {
+      usage_roles                = []
+      monitor_roles              = []
+      create_schema_roles        = []
+      modify_roles               = []
+      create_database_role_roles = []
+      privileges                 = {}
+    })
+    database_roles = optional(map(object({
+      comment                  = optional(string, null)
+      granted_to_account_roles = optional(list(string), [])
+      database_privileges      = optional(list(string), [])
+      schema_privileges        = optional(map(list(string)), {})
+    })), {})
     schemas = optional(list(object({
       name                        = string
       comment                     = optional(string, null)
       is_transient                = optional(bool, false)
       is_managed                  = optional(bool, false)
       data_retention_time_in_days = optional(number, null)
+      owner_role                  = optional(string, null)
+      owner_outbound_privileges   = optional(string, "COPY")
+      # Parameters left null inherit the database value
+      max_data_extension_time_in_days = optional(number, null)
+      default_ddl_collation           = optional(string, null)
+      external_volume                 = optional(string, null)
+      catalog                         = optional(string, null)
+      replace_invalid_characters      = optional(bool, null)
+      storage_serialization_policy    = optional(string, null)
+      log_level                       = optional(string, null)
+      trace_level                     = optional(string, null)
+      tags                            = optional(map(string), {})
       grants = optional(object({
-        usage_roles              = optional(list(string), [])
-        create_file_format_roles = optional(list(string), [])
-        create_stage_roles       = optional(list(string), [])
-        create_table_roles       = optional(list(string), [])
-        create_pipe_roles        = optional(list(string), [])
+        usage_roles                    = optional(list(string), [])
+        create_file_format_roles       = optional(list(string), [])
+        create_stage_roles             = optional(list(string), [])
+        create_table_roles             = optional(list(string), [])
+        create_pipe_roles              = optional(list(string), [])
+        create_view_roles              = optional(list(string), [])
+        create_materialized_view_roles = optional(list(string), [])
+        create_sequence_roles          = optional(list(string), [])
+        create_function_roles          = optional(list(string), [])
+        create_procedure_roles         = optional(list(string), [])
+        create_stream_roles            = optional(list(string), [])
+        create_task_roles              = optional(list(string), [])
+        create_dynamic_table_roles     = optional(list(string), [])
+        monitor_roles                  = optional(list(string), [])
+        privileges                     = optional(map(list(string)), {})
         }), {
-        usage_roles              = []
-        create_file_format_roles = []
-        create_stage_roles       = []
-        create_table_roles       = []
-        create_pipe_roles        = []
+        usage_roles                    = []
+        create_file_format_roles       = []
+        create_stage_roles             = []
+        create_table_roles             = []
+        create_pipe_roles              = []
+        create_view_roles              = []
+        create_materialized_view_roles = []
+        create_sequence_roles          = []
+        create_function_roles          = []
+        create_procedure_roles         = []
+        create_stream_roles            = []
+        create_task_roles              = []
+        create_dynamic_table_roles     = []
+        monitor_roles                  = []
+        privileges                     = {}
       })
+      future_grants      = optional(map(map(list(string))), {})
+      all_objects_grants = optional(map(map(list(string))), {})
+      access_roles = optional(object({
+        enabled                = optional(bool, true)
+        ro_granted_to_roles    = optional(list(string), [])
+        rw_granted_to_roles    = optional(list(string), [])
+        owner_granted_to_roles = optional(list(string), [])
+      }), {})
     })), [])
+    # Schemas keyed by a stable identifier, so changing name renames the
+    # schema in place instead of replacing it. Keep the type in step with
+    # schemas; TestPlanSchemaFormsMatch plans both with every attribute set.
+    schemas_by_key = optional(map(object({
+      name                        = string
+      comment                     = optional(string, null)
+      is_transient                = optional(bool, false)
+      is_managed                  = optional(bool, false)
+      data_retention_time_in_days = optional(number, null)
+      owner_role                  = optional(string, null)
+      owner_outbound_privileges   = optional(string, "COPY")
+      # Parameters left null inherit the database value
+      max_data_extension_time_in_days = optional(number, null)
+      default_ddl_collation           = optional(string, null)
+      external_volume                 = optional(string, null)
+      catalog                         = optional(string, null)
+      replace_invalid_characters      = optional(bool, null)
+      storage_serialization_policy    = optional(string, null)
+      log_level                       = optional(string, null)
+      trace_level                     = optional(string, null)
+      tags                            = optional(map(string), {})
+      grants = optional(object({
+        usage_roles                    = optional(list(string), [])
+        create_file_format_roles       = optional(list(string), [])
+        create_stage_roles             = optional(list(string), [])
+        create_table_roles             = optional(list(string), [])
+        create_pipe_roles              = optional(list(string), [])
+        create_view_roles              = optional(list(string), [])
+        create_materialized_view_roles = optional(list(string), [])
+        create_sequence_roles          = optional(list(string), [])
+        create_function_roles          = optional(list(string), [])
+        create_procedure_roles         = optional(list(string), [])
+        create_stream_roles            = optional(list(string), [])
+        create_task_roles              = optional(list(string), [])
+        create_dynamic_table_roles     = optional(list(string), [])
+        monitor_roles                  = optional(list(string), [])
+        privileges                     = optional(map(list(string)), {})
+        }), {
+        usage_roles                    = []
+        create_file_format_roles       = []
+        create_stage_roles             = []
+        create_table_roles             = []
+        create_pipe_roles              = []
+        create_view_roles              = []
+        create_materialized_view_roles = []
+        create_sequence_roles          = []
+        create_function_roles          = []
+        create_procedure_roles         = []
+        create_stream_roles            = []
+        create_task_roles              = []
+        create_dynamic_table_roles     = []
+        monitor_roles                  = []
+        privileges                     = {}
+      })
+      future_grants      = optional(map(map(list(string))), {})
+      all_objects_grants = optional(map(map(list(string))), {})
+      access_roles = optional(object({
+        enabled                = optional(bool, true)
+        ro_granted_to_roles    = optional(list(string), [])
+        rw_granted_to_roles    = optional(list(string), [])
+        owner_granted_to_roles = optional(list(string), [])
+      }), {})
+    })), {})
   }))
   default = {}
 
@@ -47,12 +181,53 @@ variable "database_configs" {
   validation {
     condition = alltrue([
       for db in var.database_configs : alltrue([
-        for schema in db.schemas : length(schema.name) > 0
+        for schema in concat(db.schemas, values(db.schemas_by_key)) : length(schema.name) > 0
       ])
     ])
     error_message = "Schema name must not be empty."
   }
 
+  validation {
+    condition = alltrue(flatten([
+      for db_key, db in var.database_configs : concat(
+        [replace(replace(db_key, "/", ""), ".", "") == db_key],
+        [for schema in concat(db.schemas, values(db.schemas_by_key)) : replace(schema.name, "/", "") == schema.name],
+        [for schema_key in keys(db.schemas_by_key) : replace(schema_key, "/", "") == schema_key],
+        [for role_name, role in db.database_roles : replace(role_name, "/", "") == role_name],
+      )
+    ]))
+    error_message = "database_configs keys, schema names, schemas_by_key keys and database role names must not contain \"/\", which separates the parts of resource keys, and database_configs keys must not contain \".\", which separates them from schema and database role names."
+  }
+
+  # List schemas are keyed by name and share the key space with schemas_by_key
+  validation {
+    condition = alltrue([
+      for db in var.database_configs : alltrue([
+        for schema in db.schemas : !contains(keys(db.schemas_by_key), schema.name)
+      ])
+    ])
+    error_message = "schemas_by_key keys must not match the name of a schema in the schemas list of the same database."
+  }
+
+  # Unquoted identifiers resolve to upper case in Snowflake, so "raw" and
+  # "RAW" name the same object, and the drift detector matches names the same
+  # way. Names that are not valid unquoted identifiers are compared as written.
+  validation {
+    condition = alltrue([
+      for db in var.database_configs : length(distinct([
+        for schema in concat(db.schemas, values(db.schemas_by_key)) : can(regex("^[A-Za-z_][A-Za-z0-9_$]*$", schema.name)) ? upper(schema.name) : schema.name
+      ])) == length(db.schemas) + length(db.schemas_by_key)
+    ])
+    error_message = "Schema names must be unique within a database. Unquoted names are case-insensitive, so \"raw\" and \"RAW\" are the same schema."
+  }
+
+  validation {
+    condition = length(distinct([
+      for db in var.database_configs : can(regex("^[A-Za-z_][A-Za-z0-9_$]*$", db.name)) ? upper(db.name) : db.name
+    ])) == length(var.database_configs)
+    error_message = "Each database name must be used by only one database_configs key. Unquoted names are case-insensitive, so \"sales_db\" and \"SALES_DB\" are the same database."
+  }
+
   validation {
     condition = alltrue([
       for db in var.database_configs : db.data_retention_time_in_days >= 0
@@ -63,9 +238,240 @@ variable "database_configs" {
   validation {
     condition = alltrue([
       for db in var.database_configs : alltrue([
-        for schema in db.schemas : coalesce(schema.data_retention_time_in_days, 0) >= 0
+        for schema in concat(db.schemas, values(db.schemas_by_key)) : coalesce(schema.data_retention_time_in_days, 0) >= 0
       ])
     ])
     error_message = "Schema data_retention_time_in_days must be >= 0 or null."
   }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : concat(
+        [contains(["COPY", "REVOKE"], upper(db.owner_outbound_privileges))],
+        [for schema in concat(db.schemas, values(db.schemas_by_key)) : contains(["COPY", "REVOKE"], upper(schema.owner_outbound_privileges))]
+      )
+    ]))
+    error_message = "owner_outbound_privileges must be COPY or REVOKE."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : concat(
+        [db.max_data_extension_time_in_days == null ? true : db.max_data_extension_time_in_days >= 0 && db.max_data_extension_time_in_days <= 90],
+        [for schema in concat(db.schemas, values(db.schemas_by_key)) : schema.max_data_extension_time_in_days == null ? true : schema.max_data_extension_time_in_days >= 0 && schema.max_data_extension_time_in_days <= 90]
+      )
+    ]))
+    error_message = "max_data_extension_time_in_days must be between 0 and 90 or null."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : concat(
+        [db.default_ddl_collation == null ? true : can(regex("^[A-Za-z0-9_-]+$", db.default_ddl_collation))],
+        [for schema in concat(db.schemas, values(db.schemas_by_key)) : schema.default_ddl_collation == null ? true : can(regex("^[A-Za-z0-9_-]+$", schema.default_ddl_collation))]
+      )
+    ]))
+    error_message = "default_ddl_collation must be a collation specification such as en-ci or utf8."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : concat(
+        [(db.external_volume == null ? true : length(db.external_volume) > 0) && (db.catalog == null ? true : length(db.catalog) > 0)],
+        [for schema in concat(db.schemas, values(db.schemas_by_key)) : (schema.external_volume == null ? true : length(schema.external_volume) > 0) && (schema.catalog == null ? true : length(schema.catalog) > 0)]
+      )
+    ]))
+    error_message = "external_volume and catalog must not be empty; use null to inherit."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : concat(
+        [db.storage_serialization_policy == null ? true : contains(["COMPATIBLE", "OPTIMIZED"], upper(db.storage_serialization_policy))],
+        [for schema in concat(db.schemas, values(db.schemas_by_key)) : schema.storage_serialization_policy == null ? true : contains(["COMPATIBLE", "OPTIMIZED"], upper(schema.storage_serialization_policy))]
+      )
+    ]))
+    error_message = "storage_serialization_policy must be COMPATIBLE or OPTIMIZED."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : concat(
+        [db.log_level == null ? true : contains(["TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL", "OFF"], upper(db.log_level))],
+        [for schema in concat(db.schemas, values(db.schemas_by_key)) : schema.log_level == null ? true : contains(["TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL", "OFF"], upper(schema.log_level))]
+      )
+    ]))
+    error_message = "log_level must be one of: TRACE, DEBUG, INFO, WARN, ERROR, FATAL, OFF."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : concat(
+        [db.trace_level == null ? true : contains(["ALWAYS", "ON_EVENT", "OFF"], upper(db.trace_level))],
+        [for schema in concat(db.schemas, values(db.schemas_by_key)) : schema.trace_level == null ? true : contains(["ALWAYS", "ON_EVENT", "OFF"], upper(schema.trace_level))]
+      )
+    ]))
+    error_message = "trace_level must be one of: ALWAYS, ON_EVENT, OFF."
+  }
+
+  validation {
+    condition = alltrue([
+      for db in var.database_configs :
+      db.event_table == null ? true : can(regex("^[^.]+[.][^.]+[.][^.]+$", db.event_table))
+    ])
+    error_message = "event_table must be a fully qualified <database>.<schema>.<table> name."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : concat(
+        [for tag in keys(db.tags) : can(regex("^[^.]+[.][^.]+[.][^.]+$", tag))],
+        flatten([for schema in concat(db.schemas, values(db.schemas_by_key)) : [for tag in keys(schema.tags) : can(regex("^[^.]+[.][^.]+[.][^.]+$", tag))]])
+      )
+    ]))
+    error_message = "tags keys must be fully qualified <database>.<schema>.<tag> names."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : [
+        for privilege in keys(db.grants.privileges) : contains([
+          "APPLYBUDGET", "CREATE DATABASE ROLE", "CREATE SCHEMA", "MODIFY",
+          "MONITOR", "REFERENCE_USAGE", "USAGE",
+        ], upper(privilege))
+      ]
+    ]))
+    error_message = "Database grants.privileges keys must be one of: APPLYBUDGET, CREATE DATABASE ROLE, CREATE SCHEMA, MODIFY, MONITOR, REFERENCE_USAGE, USAGE."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : [
+        for schema in concat(db.schemas, values(db.schemas_by_key)) : [
+          for privilege in keys(schema.grants.privileges) : contains([
+            "ADD SEARCH OPTIMIZATION", "APPLYBUDGET", "CREATE ALERT", "CREATE CORTEX SEARCH SERVICE",
+            "CREATE DYNAMIC TABLE", "CREATE EVENT TABLE", "CREATE EXTERNAL TABLE", "CREATE FILE FORMAT",
+            "CREATE FUNCTION", "CREATE GIT REPOSITORY", "CREATE ICEBERG TABLE", "CREATE IMAGE REPOSITORY",
+            "CREATE MASKING POLICY", "CREATE MATERIALIZED VIEW", "CREATE MODEL", "CREATE NETWORK RULE",
+            "CREATE NOTEBOOK", "CREATE PASSWORD POLICY", "CREATE PIPE", "CREATE PROCEDURE",
+            "CREATE ROW ACCESS POLICY", "CREATE SECRET", "CREATE SEQUENCE", "CREATE SERVICE",
+            "CREATE SESSION POLICY", "CREATE SNAPSHOT", "CREATE STAGE", "CREATE STREAM",
+            "CREATE STREAMLIT", "CREATE TABLE", "CREATE TAG", "CREATE TASK", "CREATE VIEW",
+            "MODIFY", "MONITOR", "USAGE",
+          ], upper(privilege))
+        ]
+      ]
+    ]))
+    error_message = "Schema grants.privileges keys must be valid schema privileges (e.g. USAGE, MONITOR, MODIFY, CREATE TABLE, CREATE VIEW, CREATE TAG). See README for the full list."
+  }
+
+  # A role under the same privilege in a role list and in the privileges map
+  # would get two resources managing one grant, and removing either entry
+  # would revoke the privilege the other still declares
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : [
+        for privilege, roles in {
+          "USAGE"                = db.grants.usage_roles
+          "MONITOR"              = db.grants.monitor_roles
+          "CREATE SCHEMA"        = db.grants.create_schema_roles
+          "MODIFY"               = db.grants.modify_roles
+          "CREATE DATABASE ROLE" = db.grants.create_database_role_roles
+          } : [
+          for role in roles : !contains(flatten([
+            for key, privilege_roles in db.grants.privileges : privilege_roles if upper(key) == privilege
+          ]), role)
+        ]
+      ]
+    ]))
+    error_message = "A role must not be granted the same database privilege through both a grants role list (such as monitor_roles) and grants.privileges."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : [
+        for schema in concat(db.schemas, values(db.schemas_by_key)) : [
+          for privilege, roles in {
+            "USAGE"                    = schema.grants.usage_roles
+            "CREATE FILE FORMAT"       = schema.grants.create_file_format_roles
+            "CREATE STAGE"             = schema.grants.create_stage_roles
+            "CREATE TABLE"             = schema.grants.create_table_roles
+            "CREATE PIPE"              = schema.grants.create_pipe_roles
+            "CREATE VIEW"              = schema.grants.create_view_roles
+            "CREATE MATERIALIZED VIEW" = schema.grants.create_materialized_view_roles
+            "CREATE SEQUENCE"          = schema.grants.create_sequence_roles
+            "CREATE FUNCTION"          = schema.grants.create_function_roles
+            "CREATE PROCEDURE"         = schema.grants.create_procedure_roles
+            "CREATE STREAM"            = schema.grants.create_stream_roles
+            "CREATE TASK"              = schema.grants.create_task_roles
+            "CREATE DYNAMIC TABLE"     = schema.grants.create_dynamic_table_roles
+            "MONITOR"                  = schema.grants.monitor_roles
+            } : [
+            for role in roles : !contains(flatten([
+              for key, privilege_roles in schema.grants.privileges : privilege_roles if upper(key) == privilege
+            ]), role)
+          ]
+        ]
+      ]
+    ]))
+    error_message = "A role must not be granted the same schema privilege through both a grants role list (such as create_table_roles) and grants.privileges."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : [
+        for role in values(db.database_roles) : [
+          for schema_name in keys(role.schema_privileges) : contains([for schema in concat(db.schemas, values(db.schemas_by_key)) : schema.name], schema_name)
+        ]
+      ]
+    ]))
+    error_message = "database_roles schema_privileges keys must name a schema declared in the same database."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : [
+        for schema in concat(db.schemas, values(db.schemas_by_key)) : [
+          for object_type in keys(schema.future_grants) : contains([
+            "ALERTS", "DYNAMIC TABLES", "EVENT TABLES", "EXTERNAL TABLES", "FILE FORMATS",
+            "FUNCTIONS", "ICEBERG TABLES", "MATERIALIZED VIEWS", "PIPES", "PROCEDURES",
+            "SEQUENCES", "STAGES", "STREAMS", "TABLES", "TASKS", "VIEWS",
+          ], upper(object_type))
+        ]
+      ]
+    ]))
+    error_message = "Schema future_grants keys must be plural object types: ALERTS, DYNAMIC TABLES, EVENT TABLES, EXTERNAL TABLES, FILE FORMATS, FUNCTIONS, ICEBERG TABLES, MATERIALIZED VIEWS, PIPES, PROCEDURES, SEQUENCES, STAGES, STREAMS, TABLES, TASKS, VIEWS."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for db in var.database_configs : [
+        for schema in concat(db.schemas, values(db.schemas_by_key)) : [
+          for object_type in keys(schema.all_objects_grants) : contains([
+            "ALERTS", "DYNAMIC TABLES", "EVENT TABLES", "EXTERNAL TABLES", "FILE FORMATS",
+            "FUNCTIONS", "ICEBERG TABLES", "MATERIALIZED VIEWS", "PIPES", "PROCEDURES",
+            "SEQUENCES", "STAGES", "STREAMS", "TABLES", "TASKS", "VIEWS",
+          ], upper(object_type))
+        ]
+      ]
+    ]))
+    error_message = "Schema all_objects_grants keys must be plural object types: ALERTS, DYNAMIC TABLES, EVENT TABLES, EXTERNAL TABLES, FILE FORMATS, FUNCTIONS, ICEBERG TABLES, MATERIALIZED VIEWS, PIPES, PROCEDURES, SEQUENCES, STAGES, STREAMS, TABLES, TASKS, VIEWS."
+  }
+}
+
+variable "access_roles" {
+  description = "Opt-in generation of RO, RW and OWNER access roles for every schema"
+  type = object({
+    enabled       = optional(bool, false)
+    name_template = optional(string, "{database}_{schema}_{level}")
+    parent_role   = optional(string, null)
+  })
+  default = {}
+
+  validation {
+    condition = !var.access_roles.enabled || alltrue([
+      for placeholder in ["{database}", "{schema}", "{level}"] : replace(var.access_roles.name_template, placeholder, "") != var.access_roles.name_template
+    ])
+    error_message = "access_roles.name_template must contain {database}, {schema} and {level}."
+  }
 }